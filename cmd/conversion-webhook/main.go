@@ -0,0 +1,41 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+
+	"k8s.io/klog/v2"
+
+	"github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/client/clientset/versioned/scheme"
+	"github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/conversion"
+)
+
+var (
+	port     = flag.Int("port", 8443, "The port to serve the conversion webhook on.")
+	certFile = flag.String("tls-cert-file", "", "Path to the TLS certificate. Serves plain HTTP if unset, for local testing only.")
+	keyFile  = flag.String("tls-private-key-file", "", "Path to the TLS private key.")
+)
+
+func main() {
+	klog.InitFlags(nil)
+	flag.Parse()
+
+	mux := http.NewServeMux()
+	mux.Handle("/convert", conversion.NewHandler(scheme.Scheme))
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	addr := fmt.Sprintf(":%d", *port)
+	var err error
+	if *certFile != "" {
+		klog.InfoS("Serving conversion webhook", "address", addr, "tls", true)
+		err = http.ListenAndServeTLS(addr, *certFile, *keyFile, mux)
+	} else {
+		klog.InfoS("Serving conversion webhook", "address", addr, "tls", false)
+		err = http.ListenAndServe(addr, mux)
+	}
+	klog.ErrorS(err, "Error serving conversion webhook")
+	klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+}
@@ -5,10 +5,9 @@ import (
 	"flag"
 	"fmt"
 
-	"github.com/golang/glog"
-
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/klog/v2"
 
 	clientset "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/client/clientset/versioned"
 )
@@ -19,21 +18,25 @@ var (
 )
 
 func main() {
+	klog.InitFlags(nil)
 	flag.Parse()
 
 	cfg, err := clientcmd.BuildConfigFromFlags(*master, *kuberconfig)
 	if err != nil {
-		glog.Fatalf("Error building kubeconfig: %v", err)
+		klog.ErrorS(err, "Error building kubeconfig")
+		klog.FlushAndExit(klog.ExitFlushTimeout, 1)
 	}
 
 	exampleClient, err := clientset.NewForConfig(cfg)
 	if err != nil {
-		glog.Fatalf("Error building example clientset: %v", err)
+		klog.ErrorS(err, "Error building example clientset")
+		klog.FlushAndExit(klog.ExitFlushTimeout, 1)
 	}
 
 	list, err := exampleClient.K8sCniCncfIoV1().NetworkAttachmentDefinitions("default").List(context.TODO(), metav1.ListOptions{})
 	if err != nil {
-		glog.Fatalf("Error listing all network attachment definitions: %v", err)
+		klog.ErrorS(err, "Error listing all network attachment definitions")
+		klog.FlushAndExit(klog.ExitFlushTimeout, 1)
 	}
 
 	for _, nad := range list.Items {
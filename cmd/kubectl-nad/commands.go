@@ -0,0 +1,440 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/containernetworking/cni/libcni"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+
+	v1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+	clientset "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/client/clientset/versioned"
+	"github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/ipam"
+	"github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/utils"
+	"github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/validation"
+)
+
+// cli runs the commands against its clients and writes to out
+type cli struct {
+	out        io.Writer
+	nadClient  clientset.Interface
+	kubeClient kubernetes.Interface
+}
+
+// PodAttachment is a pod attached to a network, as printed by pods-using
+type PodAttachment struct {
+	Namespace string `json:"namespace"`
+	Pod       string `json:"pod"`
+	Interface string `json:"interface,omitempty"`
+}
+
+func (c *cli) list(opts *options, args []string) error {
+	if len(args) != 0 {
+		return fmt.Errorf("list takes no arguments")
+	}
+
+	namespace := opts.namespace
+	if opts.allNamespaces {
+		namespace = metav1.NamespaceAll
+	}
+	list, err := c.nadClient.K8sCniCncfIoV1().NetworkAttachmentDefinitions(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	list.TypeMeta = metav1.TypeMeta{APIVersion: v1.SchemeGroupVersion.String(), Kind: "NetworkAttachmentDefinitionList"}
+	sort.Slice(list.Items, func(i, j int) bool {
+		if list.Items[i].Namespace != list.Items[j].Namespace {
+			return list.Items[i].Namespace < list.Items[j].Namespace
+		}
+		return list.Items[i].Name < list.Items[j].Name
+	})
+
+	return c.print(opts.output, list, func(w io.Writer) {
+		fmt.Fprintln(w, "NAMESPACE\tNAME\tTYPE\tIPAM\tRESOURCE")
+		for i := range list.Items {
+			nad := &list.Items[i]
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", nad.Namespace, nad.Name,
+				orNone(strings.Join(utils.PluginTypes(nad.Spec.Config), ",")),
+				orNone(strings.Join(ipamTypes(nad), ",")),
+				orNone(nad.Annotations[v1.ResourceNameAnnot]))
+		}
+	})
+}
+
+func (c *cli) describe(opts *options, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("describe takes the network name")
+	}
+
+	nad, err := c.nadClient.K8sCniCncfIoV1().NetworkAttachmentDefinitions(opts.namespace).Get(context.TODO(), args[0], metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	nad.TypeMeta = metav1.TypeMeta{APIVersion: v1.SchemeGroupVersion.String(), Kind: "NetworkAttachmentDefinition"}
+
+	return c.print(opts.output, nad, func(w io.Writer) {
+		fmt.Fprintf(w, "Name:\t%s\n", nad.Name)
+		fmt.Fprintf(w, "Namespace:\t%s\n", nad.Namespace)
+		fmt.Fprintf(w, "Resource:\t%s\n", orNone(nad.Annotations[v1.ResourceNameAnnot]))
+		fmt.Fprintf(w, "Plugins:\t%s\n", orNone(strings.Join(utils.PluginTypes(nad.Spec.Config), ", ")))
+
+		configs, err := ipam.GetIPAMConfigsFromNAD(nad)
+		if err != nil {
+			fmt.Fprintf(w, "IPAM:\t<invalid: %v>\n", err)
+		}
+		for _, config := range configs {
+			ranges := make([]string, 0, len(config.Ranges))
+			for _, r := range config.Ranges {
+				ranges = append(ranges, r.String())
+			}
+			fmt.Fprintf(w, "IPAM:\t%s\n", strings.Join(append([]string{config.Type}, ranges...), " "))
+		}
+
+		keys := make([]string, 0, len(nad.Annotations))
+		for k := range nad.Annotations {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		if len(keys) == 0 {
+			fmt.Fprintf(w, "Annotations:\t%s\n", orNone(""))
+		} else {
+			fmt.Fprintln(w, "Annotations:")
+			for _, k := range keys {
+				fmt.Fprintf(w, "  %s=%s\n", k, nad.Annotations[k])
+			}
+		}
+
+		fmt.Fprintln(w, "Config:")
+		if nad.Spec.Config == "" {
+			fmt.Fprintln(w, "  <from the CNI configuration directory>")
+			return
+		}
+		var config bytes.Buffer
+		if err := json.Indent(&config, []byte(nad.Spec.Config), "  ", "  "); err != nil {
+			fmt.Fprintf(w, "  %s\n", nad.Spec.Config)
+			return
+		}
+		fmt.Fprintf(w, "  %s\n", config.String())
+	})
+}
+
+func (c *cli) validate(opts *options, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("validate takes one or more files")
+	}
+
+	registry := validation.NewDefaultRegistry()
+	failed := false
+	for _, file := range args {
+		nads, err := readNetworkAttachmentDefinitions(file)
+		if err != nil {
+			failed = true
+			fmt.Fprintf(c.out, "%s: %v\n", file, err)
+			continue
+		}
+		for _, nad := range nads {
+			if err := validateNetworkAttachmentDefinition(registry, nad); err != nil {
+				failed = true
+				fmt.Fprintf(c.out, "%s: %s/%s: %v\n", file, nad.Namespace, nad.Name, err)
+				continue
+			}
+			fmt.Fprintf(c.out, "%s: %s/%s: valid\n", file, nad.Namespace, nad.Name)
+		}
+	}
+	if failed {
+		return fmt.Errorf("validation failed")
+	}
+	return nil
+}
+
+func (c *cli) convert(opts *options, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("convert takes one or more files or directories")
+	}
+
+	if opts.configDir != "" {
+		for _, file := range args {
+			nads, err := readNetworkAttachmentDefinitions(file)
+			if err != nil {
+				return fmt.Errorf("%s: %v", file, err)
+			}
+			for _, nad := range nads {
+				path, err := utils.NADToConfigFile(nad, opts.configDir)
+				if err != nil {
+					return fmt.Errorf("%s: %s/%s: %v", file, nad.Namespace, nad.Name, err)
+				}
+				fmt.Fprintf(c.out, "%s/%s: wrote %s\n", nad.Namespace, nad.Name, path)
+			}
+		}
+		return nil
+	}
+
+	var files []string
+	for _, arg := range args {
+		info, err := os.Stat(arg)
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			files = append(files, arg)
+			continue
+		}
+		dirFiles, err := libcni.ConfFiles(arg, []string{".conf", ".json", ".conflist"})
+		if err != nil {
+			return err
+		}
+		files = append(files, dirFiles...)
+	}
+
+	docs := make([][]byte, 0, len(files))
+	for _, file := range files {
+		nad, err := utils.ConfigFileToNAD(file, opts.namespace)
+		if err != nil {
+			return err
+		}
+		var data []byte
+		switch opts.output {
+		case "", "yaml":
+			data, err = yaml.Marshal(nad)
+		case "json":
+			data, err = json.MarshalIndent(nad, "", "    ")
+			data = append(data, '\n')
+		default:
+			return fmt.Errorf("unknown output format %q", opts.output)
+		}
+		if err != nil {
+			return err
+		}
+		docs = append(docs, data)
+	}
+
+	separator := []byte("---\n")
+	if opts.output == "json" {
+		separator = nil
+	}
+	_, err := c.out.Write(bytes.Join(docs, separator))
+	return err
+}
+
+// NetworkDiff holds the differences between a live network and a manifest,
+// as printed by diff
+type NetworkDiff struct {
+	Namespace   string        `json:"namespace"`
+	Name        string        `json:"name"`
+	Missing     bool          `json:"missing,omitempty"`
+	Differences []ValueChange `json:"differences,omitempty"`
+}
+
+// ValueChange is a value changed from the live network to the manifest
+type ValueChange struct {
+	Path string      `json:"path"`
+	Live interface{} `json:"live,omitempty"`
+	File interface{} `json:"file,omitempty"`
+}
+
+func (c *cli) diff(opts *options, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("diff takes one or more files")
+	}
+
+	diffs := []NetworkDiff{}
+	for _, file := range args {
+		nads, err := readNetworkAttachmentDefinitions(file)
+		if err != nil {
+			return fmt.Errorf("%s: %v", file, err)
+		}
+		for _, nad := range nads {
+			namespace := nad.Namespace
+			if namespace == "" {
+				namespace = opts.namespace
+			}
+			diff := NetworkDiff{Namespace: namespace, Name: nad.Name}
+			live, err := c.nadClient.K8sCniCncfIoV1().NetworkAttachmentDefinitions(namespace).Get(context.TODO(), nad.Name, metav1.GetOptions{})
+			switch {
+			case apierrors.IsNotFound(err):
+				diff.Missing = true
+			case err != nil:
+				return err
+			default:
+				for _, d := range utils.Diff(live, nad) {
+					diff.Differences = append(diff.Differences, ValueChange{Path: d.Path, Live: d.From, File: d.To})
+				}
+			}
+			if diff.Missing || len(diff.Differences) > 0 {
+				diffs = append(diffs, diff)
+			}
+		}
+	}
+
+	err := c.print(opts.output, diffs, func(w io.Writer) {
+		for _, diff := range diffs {
+			if diff.Missing {
+				fmt.Fprintf(w, "%s/%s: not found in the cluster\n", diff.Namespace, diff.Name)
+				continue
+			}
+			fmt.Fprintf(w, "%s/%s:\n", diff.Namespace, diff.Name)
+			for _, d := range diff.Differences {
+				fmt.Fprintf(w, "  %s\n", utils.Difference{Path: d.Path, From: d.Live, To: d.File})
+			}
+		}
+	})
+	if err != nil {
+		return err
+	}
+	if len(diffs) > 0 {
+		return fmt.Errorf("%d networks differ from the cluster", len(diffs))
+	}
+	return nil
+}
+
+func (c *cli) podsUsing(opts *options, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("pods-using takes the network name")
+	}
+	network := args[0]
+
+	podNamespace := opts.namespace
+	if opts.allNamespaces {
+		podNamespace = metav1.NamespaceAll
+	}
+	pods, err := c.kubeClient.CoreV1().Pods(podNamespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	attachments := []PodAttachment{}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		networks, err := utils.ParsePodNetworkAnnotation(pod)
+		if err != nil {
+			continue
+		}
+		for _, sel := range networks {
+			if sel.Name == network && sel.Namespace == opts.namespace {
+				attachments = append(attachments, PodAttachment{Namespace: pod.Namespace, Pod: pod.Name, Interface: sel.InterfaceRequest})
+			}
+		}
+	}
+	sort.Slice(attachments, func(i, j int) bool {
+		if attachments[i].Namespace != attachments[j].Namespace {
+			return attachments[i].Namespace < attachments[j].Namespace
+		}
+		return attachments[i].Pod < attachments[j].Pod
+	})
+
+	return c.print(opts.output, attachments, func(w io.Writer) {
+		fmt.Fprintln(w, "NAMESPACE\tPOD\tINTERFACE")
+		for _, a := range attachments {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", a.Namespace, a.Pod, orNone(a.Interface))
+		}
+	})
+}
+
+func (c *cli) status(opts *options, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("status takes the pod name")
+	}
+
+	pod, err := c.kubeClient.CoreV1().Pods(opts.namespace).Get(context.TODO(), args[0], metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	statuses, err := utils.GetNetworkStatus(pod)
+	if err != nil {
+		return err
+	}
+
+	return c.print(opts.output, statuses, func(w io.Writer) {
+		fmt.Fprintln(w, "NETWORK\tINTERFACE\tIPS\tMAC\tDEFAULT")
+		for _, s := range statuses {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%t\n", s.Name, orNone(s.Interface), orNone(strings.Join(s.IPs, ",")), orNone(s.Mac), s.Default)
+		}
+	})
+}
+
+// print writes obj as JSON or YAML, or calls table with a tab aligned writer
+func (c *cli) print(output string, obj interface{}, table func(w io.Writer)) error {
+	switch output {
+	case "":
+		w := tabwriter.NewWriter(c.out, 0, 8, 2, ' ', 0)
+		table(w)
+		return w.Flush()
+	case "json":
+		data, err := json.MarshalIndent(obj, "", "    ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(c.out, "%s\n", data)
+		return err
+	case "yaml":
+		data, err := yaml.Marshal(obj)
+		if err != nil {
+			return err
+		}
+		_, err = c.out.Write(data)
+		return err
+	default:
+		return fmt.Errorf("unknown output format %q", output)
+	}
+}
+
+// readNetworkAttachmentDefinitions decodes every NetworkAttachmentDefinition
+// of a YAML or JSON file with one or more documents
+func readNetworkAttachmentDefinitions(file string) ([]*v1.NetworkAttachmentDefinition, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	nads, err := utils.ReadNetworkAttachmentDefinitions(f)
+	if err != nil {
+		return nil, err
+	}
+	if len(nads) == 0 {
+		return nil, fmt.Errorf("no NetworkAttachmentDefinition found")
+	}
+	return nads, nil
+}
+
+// validateNetworkAttachmentDefinition checks that the config is a valid CNI
+// config and runs the plugin validators on it
+func validateNetworkAttachmentDefinition(registry *validation.Registry, nad *v1.NetworkAttachmentDefinition) error {
+	if nad.Spec.Config == "" {
+		return nil
+	}
+	if _, err := utils.GetCNIConfigFromSpec(nad.Spec.Config, nad.Name); err != nil {
+		return err
+	}
+	return registry.Validate(nad)
+}
+
+// ipamTypes returns the IPAM types used by the network
+func ipamTypes(nad *v1.NetworkAttachmentDefinition) []string {
+	configs, err := ipam.GetIPAMConfigsFromNAD(nad)
+	if err != nil {
+		return []string{"<invalid>"}
+	}
+	types := make([]string, 0, len(configs))
+	for _, config := range configs {
+		types = append(types, config.Type)
+	}
+	return types
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "<none>"
+	}
+	return s
+}
@@ -0,0 +1,158 @@
+// kubectl-nad is a kubectl plugin to inspect NetworkAttachmentDefinitions
+// and the pods attached to them. Install it in the PATH and run it as
+// "kubectl nad <command>".
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+
+	clientset "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/client/clientset/versioned"
+)
+
+// clientsFunc returns the clients for the kubeconfig and the namespace of
+// its current context
+type clientsFunc func(kubeconfig string) (clientset.Interface, kubernetes.Interface, string, error)
+
+// options are the flags shared by all commands
+type options struct {
+	kubeconfig    string
+	namespace     string
+	allNamespaces bool
+	output        string
+	configDir     string
+}
+
+type command struct {
+	usage string
+	// local commands do not talk to the API server
+	local bool
+	run   func(c *cli, opts *options, args []string) error
+}
+
+var commands = map[string]command{
+	"list":       {usage: "list [-A] [-n namespace]", run: (*cli).list},
+	"describe":   {usage: "describe <network> [-n namespace]", run: (*cli).describe},
+	"validate":   {usage: "validate <file>...", local: true, run: (*cli).validate},
+	"diff":       {usage: "diff <file>... [-n namespace]", run: (*cli).diff},
+	"convert":    {usage: "convert <CNI config file or directory>... [-n namespace] | convert -config-dir <dir> <file>...", local: true, run: (*cli).convert},
+	"pods-using": {usage: "pods-using <network> [-A] [-n namespace]", run: (*cli).podsUsing},
+	"status":     {usage: "status <pod> [-n namespace]", run: (*cli).status},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr, newClients))
+}
+
+func newClients(kubeconfig string) (clientset.Interface, kubernetes.Interface, string, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = kubeconfig
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{})
+
+	namespace, _, err := clientConfig.Namespace()
+	if err != nil {
+		return nil, nil, "", err
+	}
+	cfg, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("error building kubeconfig: %v", err)
+	}
+	nadClient, err := clientset.NewForConfig(cfg)
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("error building network attachment definition clientset: %v", err)
+	}
+	kubeClient, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("error building kubernetes clientset: %v", err)
+	}
+	return nadClient, kubeClient, namespace, nil
+}
+
+// run executes the command line and returns the exit code
+func run(args []string, stdout, stderr io.Writer, newClients clientsFunc) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return 2
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "error: unknown command %q\n", args[0])
+		usage(stderr)
+		return 2
+	}
+
+	opts := &options{}
+	fs := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.StringVar(&opts.kubeconfig, "kubeconfig", "", "Path to a kubeconfig. Defaults to $KUBECONFIG or ~/.kube/config.")
+	fs.StringVar(&opts.namespace, "namespace", "", "The namespace to use. Defaults to the namespace of the current context.")
+	fs.StringVar(&opts.namespace, "n", "", "Shorthand for -namespace.")
+	fs.BoolVar(&opts.allNamespaces, "all-namespaces", false, "Use all namespaces.")
+	fs.BoolVar(&opts.allNamespaces, "A", false, "Shorthand for -all-namespaces.")
+	fs.StringVar(&opts.output, "output", "", "Output format: json or yaml. Defaults to a table.")
+	fs.StringVar(&opts.output, "o", "", "Shorthand for -output.")
+	fs.StringVar(&opts.configDir, "config-dir", "", "convert: write the networks of the manifests as .conflist files to this directory.")
+
+	positional, err := parseInterspersed(fs, args[1:])
+	if err != nil {
+		return 2
+	}
+
+	c := &cli{out: stdout}
+	if !cmd.local {
+		nadClient, kubeClient, namespace, err := newClients(opts.kubeconfig)
+		if err != nil {
+			fmt.Fprintf(stderr, "error: %v\n", err)
+			return 1
+		}
+		c.nadClient = nadClient
+		c.kubeClient = kubeClient
+		if opts.namespace == "" {
+			opts.namespace = namespace
+		}
+	}
+
+	if err := cmd.run(c, opts, positional); err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// parseInterspersed parses flags placed before, between or after the
+// positional arguments, as kubectl does
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+func usage(w io.Writer) {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	lines := make([]string, 0, len(names))
+	for _, name := range names {
+		lines = append(lines, "  kubectl nad "+commands[name].usage)
+	}
+	fmt.Fprintf(w, "Usage:\n%s\n\nAll commands accept -o json|yaml and -kubeconfig.\n", strings.Join(lines, "\n"))
+}
@@ -0,0 +1,335 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+
+	v1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+	clientset "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/client/clientset/versioned"
+	nadfake "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/client/clientset/versioned/fake"
+)
+
+func newFakeClients(t *testing.T) clientsFunc {
+	nadClient := nadfake.NewSimpleClientset()
+	kubeClient := k8sfake.NewSimpleClientset()
+	ctx := context.TODO()
+
+	for _, nad := range []*v1.NetworkAttachmentDefinition{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "bridge-net", Namespace: "default"},
+			Spec: v1.NetworkAttachmentDefinitionSpec{Config: `{"cniVersion": "1.0.0", "plugins": [
+				{"type": "bridge", "ipam": {"type": "host-local", "subnet": "10.1.0.0/24"}},
+				{"type": "tuning"}
+			]}`},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "sriov-net",
+				Namespace:   "infra",
+				Annotations: map[string]string{v1.ResourceNameAnnot: "intel.com/sriov"},
+			},
+			Spec: v1.NetworkAttachmentDefinitionSpec{Config: `{"cniVersion": "1.0.0", "type": "sriov", "ipam": {"type": "dhcp"}}`},
+		},
+	} {
+		if _, err := nadClient.K8sCniCncfIoV1().NetworkAttachmentDefinitions(nad.Namespace).Create(ctx, nad, metav1.CreateOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, pod := range []*corev1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "web",
+				Namespace: "default",
+				Annotations: map[string]string{
+					v1.NetworkAttachmentAnnot: "bridge-net@net1",
+					v1.NetworkStatusAnnot:     `[{"name": "default/bridge-net", "interface": "net1", "ips": ["10.1.0.5"], "mac": "02:00:00:00:00:01"}]`,
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "db",
+				Namespace:   "tenant",
+				Annotations: map[string]string{v1.NetworkAttachmentAnnot: "default/bridge-net, infra/sriov-net"},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "plain", Namespace: "default"},
+		},
+	} {
+		if _, err := kubeClient.CoreV1().Pods(pod.Namespace).Create(ctx, pod, metav1.CreateOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return func(string) (clientset.Interface, kubernetes.Interface, string, error) {
+		return nadClient, kubeClient, "default", nil
+	}
+}
+
+func runCommand(t *testing.T, args ...string) (string, string, int) {
+	var stdout, stderr bytes.Buffer
+	code := run(args, &stdout, &stderr, newFakeClients(t))
+	return stdout.String(), stderr.String(), code
+}
+
+func TestList(t *testing.T) {
+	out, stderr, code := runCommand(t, "list", "-A")
+	if code != 0 {
+		t.Fatalf("unexpected exit code %d: %s", code, stderr)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected a header and 2 rows, got:\n%s", out)
+	}
+	if fields := strings.Fields(lines[1]); strings.Join(fields, " ") != "default bridge-net bridge,tuning host-local <none>" {
+		t.Errorf("unexpected row %q", lines[1])
+	}
+	if fields := strings.Fields(lines[2]); strings.Join(fields, " ") != "infra sriov-net sriov dhcp intel.com/sriov" {
+		t.Errorf("unexpected row %q", lines[2])
+	}
+
+	out, _, code = runCommand(t, "list", "-o", "json")
+	if code != 0 {
+		t.Fatalf("unexpected exit code %d", code)
+	}
+	list := &v1.NetworkAttachmentDefinitionList{}
+	if err := json.Unmarshal([]byte(out), list); err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Items) != 1 || list.Items[0].Name != "bridge-net" || list.Kind != "NetworkAttachmentDefinitionList" {
+		t.Errorf("unexpected list %+v", list)
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	out, stderr, code := runCommand(t, "describe", "sriov-net", "-n", "infra")
+	if code != 0 {
+		t.Fatalf("unexpected exit code %d: %s", code, stderr)
+	}
+	words := strings.Join(strings.Fields(out), " ")
+	for _, expected := range []string{"Name: sriov-net", "Resource: intel.com/sriov", "IPAM: dhcp Annotations:", `"type": "sriov"`} {
+		if !strings.Contains(words, expected) {
+			t.Errorf("expected %q in output:\n%s", expected, out)
+		}
+	}
+
+	out, _, code = runCommand(t, "describe", "-o", "yaml", "bridge-net")
+	if code != 0 || !strings.Contains(out, "kind: NetworkAttachmentDefinition") {
+		t.Errorf("unexpected yaml output (%d):\n%s", code, out)
+	}
+
+	_, stderr, code = runCommand(t, "describe", "missing")
+	if code != 1 || !strings.Contains(stderr, "not found") {
+		t.Errorf("expected not found error, got %d: %s", code, stderr)
+	}
+}
+
+func TestPodsUsing(t *testing.T) {
+	out, stderr, code := runCommand(t, "pods-using", "bridge-net", "-A")
+	if code != 0 {
+		t.Fatalf("unexpected exit code %d: %s", code, stderr)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[1], "default") || !strings.HasPrefix(lines[2], "tenant") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+
+	out, _, _ = runCommand(t, "pods-using", "sriov-net", "-n", "infra", "-A", "-o", "json")
+	var attachments []PodAttachment
+	if err := json.Unmarshal([]byte(out), &attachments); err != nil {
+		t.Fatal(err)
+	}
+	if len(attachments) != 1 || attachments[0].Pod != "db" {
+		t.Errorf("unexpected attachments %+v", attachments)
+	}
+}
+
+func TestStatus(t *testing.T) {
+	out, stderr, code := runCommand(t, "status", "web")
+	if code != 0 {
+		t.Fatalf("unexpected exit code %d: %s", code, stderr)
+	}
+	if !strings.Contains(out, "default/bridge-net") || !strings.Contains(out, "10.1.0.5") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+
+	_, _, code = runCommand(t, "status", "plain")
+	if code != 1 {
+		t.Errorf("expected failure for pod without network status, got %d", code)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "valid.yaml")
+	invalid := filepath.Join(dir, "invalid.yaml")
+	if err := os.WriteFile(valid, []byte(`apiVersion: k8s.cni.cncf.io/v1
+kind: NetworkAttachmentDefinition
+metadata:
+  name: macvlan-net
+spec:
+  config: '{"cniVersion": "1.0.0", "type": "macvlan", "mode": "bridge"}'
+---
+apiVersion: k8s.cni.cncf.io/v1
+kind: NetworkAttachmentDefinition
+metadata:
+  name: resource-only
+`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(invalid, []byte(`apiVersion: k8s.cni.cncf.io/v1
+kind: NetworkAttachmentDefinition
+metadata:
+  name: vlan-net
+spec:
+  config: '{"cniVersion": "1.0.0", "type": "vlan", "master": "eth0", "vlanId": 5000}'
+`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	noClients := func(string) (clientset.Interface, kubernetes.Interface, string, error) {
+		t.Fatal("validate must not build clients")
+		return nil, nil, "", nil
+	}
+	if code := run([]string{"validate", valid}, &stdout, &stderr, noClients); code != 0 {
+		t.Errorf("unexpected exit code %d: %s%s", code, stdout.String(), stderr.String())
+	}
+
+	stdout.Reset()
+	if code := run([]string{"validate", valid, invalid}, &stdout, &stderr, noClients); code != 1 {
+		t.Errorf("expected exit code 1, got %d", code)
+	}
+	if !strings.Contains(stdout.String(), "vlanId 5000") {
+		t.Errorf("expected the vlan error in output:\n%s", stdout.String())
+	}
+}
+
+func TestUsage(t *testing.T) {
+	if _, _, code := runCommand(t); code != 2 {
+		t.Errorf("expected exit code 2 without command, got %d", code)
+	}
+	if _, stderr, code := runCommand(t, "frobnicate"); code != 2 || !strings.Contains(stderr, "unknown command") {
+		t.Errorf("expected unknown command, got %d: %s", code, stderr)
+	}
+	if _, stderr, code := runCommand(t, "list", "-o", "xml"); code != 1 || !strings.Contains(stderr, "unknown output format") {
+		t.Errorf("expected unknown output format, got %d: %s", code, stderr)
+	}
+}
+
+func TestConvert(t *testing.T) {
+	confDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(confDir, "10-bridge.conflist"), []byte(`{
+	"cniVersion": "1.0.0",
+	"name": "bridge-net",
+	"plugins": [{"type": "bridge"}, {"type": "portmap", "capabilities": {"portMappings": true}}]
+}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(confDir, "20-macvlan.conf"), []byte(`{"cniVersion": "0.4.0", "name": "macvlan-net", "type": "macvlan"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	noClients := func(string) (clientset.Interface, kubernetes.Interface, string, error) {
+		t.Fatal("convert must not build clients")
+		return nil, nil, "", nil
+	}
+	if code := run([]string{"convert", confDir, "-n", "infra"}, &stdout, &stderr, noClients); code != 0 {
+		t.Fatalf("unexpected exit code %d: %s", code, stderr.String())
+	}
+	manifest := filepath.Join(t.TempDir(), "networks.yaml")
+	if err := os.WriteFile(manifest, stdout.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	nads, err := readNetworkAttachmentDefinitions(manifest)
+	if err != nil {
+		t.Fatalf("failed to read the converted manifest: %v\n%s", err, stdout.String())
+	}
+	if len(nads) != 2 || nads[0].Name != "bridge-net" || nads[1].Name != "macvlan-net" || nads[1].Namespace != "infra" {
+		t.Fatalf("unexpected networks %+v", nads)
+	}
+
+	outDir := t.TempDir()
+	stdout.Reset()
+	if code := run([]string{"convert", "-config-dir", outDir, manifest}, &stdout, &stderr, noClients); code != 0 {
+		t.Fatalf("unexpected exit code %d: %s", code, stderr.String())
+	}
+	for _, name := range []string{"bridge-net", "macvlan-net"} {
+		if _, err := os.Stat(filepath.Join(outDir, name+".conflist")); err != nil {
+			t.Errorf("expected %s.conflist: %v", name, err)
+		}
+	}
+}
+
+func TestDiff(t *testing.T) {
+	manifest := filepath.Join(t.TempDir(), "networks.yaml")
+	if err := os.WriteFile(manifest, []byte(`apiVersion: k8s.cni.cncf.io/v1
+kind: NetworkAttachmentDefinition
+metadata:
+  name: bridge-net
+  namespace: default
+spec:
+  config: |
+    {
+      "plugins": [
+        {"type": "bridge", "ipam": {"subnet": "10.1.0.0/24", "type": "host-local"}},
+        {"type": "tuning"}
+      ],
+      "cniVersion": "1.0"
+    }
+---
+apiVersion: k8s.cni.cncf.io/v1
+kind: NetworkAttachmentDefinition
+metadata:
+  name: sriov-net
+  namespace: infra
+  annotations:
+    k8s.v1.cni.cncf.io/resourceName: intel.com/sriov
+spec:
+  config: '{"cniVersion": "1.0.0", "type": "sriov", "vlan": 10, "ipam": {"type": "dhcp"}}'
+---
+apiVersion: k8s.cni.cncf.io/v1
+kind: NetworkAttachmentDefinition
+metadata:
+  name: new-net
+spec:
+  config: '{"cniVersion": "1.0.0", "type": "macvlan"}'
+`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	out, stderr, code := runCommand(t, "diff", manifest)
+	if code != 1 || !strings.Contains(stderr, "2 networks differ") {
+		t.Fatalf("unexpected exit code %d: %s", code, stderr)
+	}
+	if strings.Contains(out, "bridge-net") {
+		t.Errorf("formatting changes must not be reported:\n%s", out)
+	}
+	for _, expected := range []string{"infra/sriov-net:\n  plugins[0].vlan: <none> -> 10\n", "default/new-net: not found in the cluster"} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("expected %q in output:\n%s", expected, out)
+		}
+	}
+
+	out, _, _ = runCommand(t, "diff", manifest, "-o", "json")
+	var diffs []NetworkDiff
+	if err := json.Unmarshal([]byte(out), &diffs); err != nil {
+		t.Fatal(err)
+	}
+	if len(diffs) != 2 || diffs[0].Differences[0].Path != "plugins[0].vlan" || !diffs[1].Missing {
+		t.Errorf("unexpected diffs %+v", diffs)
+	}
+}
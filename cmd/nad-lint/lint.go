@@ -0,0 +1,346 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	kubescheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/yaml"
+
+	v1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+	nadscheme "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/client/clientset/versioned/scheme"
+	"github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/utils"
+	"github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/validation"
+)
+
+// Rules reported by the linter
+const (
+	RuleDecode            = "decode"
+	RuleInvalidConfig     = "invalid-config"
+	RuleInvalidAnnotation = "invalid-network-annotation"
+	RuleMissingNetwork    = "missing-network"
+)
+
+// ruleDescriptions describe the rules in the reports
+var ruleDescriptions = map[string]string{
+	RuleDecode:            "The document is not a valid Kubernetes object",
+	RuleInvalidConfig:     "The CNI configuration of the network is invalid",
+	RuleInvalidAnnotation: "The k8s.v1.cni.cncf.io/networks annotation of the pod template cannot be parsed",
+	RuleMissingNetwork:    "The pod template references a network that is not part of the input",
+}
+
+var (
+	scheme       = runtime.NewScheme()
+	deserializer runtime.Decoder
+
+	documentSeparator = regexp.MustCompile(`^---\s*$`)
+)
+
+func init() {
+	utilruntime.Must(kubescheme.AddToScheme(scheme))
+	utilruntime.Must(nadscheme.AddToScheme(scheme))
+	deserializer = serializer.NewCodecFactory(scheme).UniversalDeserializer()
+}
+
+// Finding is a problem found in an input document
+type Finding struct {
+	File string
+	// Line is the first line of the document in the file
+	Line    int
+	Object  string
+	Rule    string
+	Message string
+}
+
+// Object is an object checked by the linter
+type Object struct {
+	File string
+	Line int
+	// Name is kind/namespace/name
+	Name string
+}
+
+// Result holds the checked objects and the findings of a lint run
+type Result struct {
+	Objects  []Object
+	Findings []Finding
+}
+
+// document is one decoded object of an input file
+type document struct {
+	file string
+	line int
+	obj  runtime.Object
+}
+
+// Linter checks NetworkAttachmentDefinitions and the network annotations of
+// pod templates
+type Linter struct {
+	// DefaultNamespace is used for objects without a namespace
+	DefaultNamespace string
+	Registry         *validation.Registry
+}
+
+// Lint checks every YAML or JSON file given, walking directories
+func (l *Linter) Lint(paths []string) (*Result, error) {
+	files, err := collectFiles(paths)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{}
+	var docs []document
+	for _, file := range files {
+		fileDocs, findings, err := readDocuments(file)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, fileDocs...)
+		result.Findings = append(result.Findings, findings...)
+	}
+
+	networks := map[string]bool{}
+	clusterNetworks := map[string]bool{}
+	for _, doc := range docs {
+		switch obj := doc.obj.(type) {
+		case *v1.NetworkAttachmentDefinition:
+			networks[l.namespace(&obj.ObjectMeta)+"/"+obj.Name] = true
+		case *v1.ClusterNetworkAttachmentDefinition:
+			clusterNetworks[obj.Name] = true
+		}
+	}
+
+	for _, doc := range docs {
+		switch obj := doc.obj.(type) {
+		case *v1.NetworkAttachmentDefinition:
+			name := fmt.Sprintf("NetworkAttachmentDefinition/%s/%s", l.namespace(&obj.ObjectMeta), obj.Name)
+			result.Objects = append(result.Objects, Object{File: doc.file, Line: doc.line, Name: name})
+			if err := l.validateConfig(obj); err != nil {
+				result.Findings = append(result.Findings, Finding{File: doc.file, Line: doc.line, Object: name, Rule: RuleInvalidConfig, Message: err.Error()})
+			}
+		case *v1.ClusterNetworkAttachmentDefinition:
+			name := "ClusterNetworkAttachmentDefinition/" + obj.Name
+			result.Objects = append(result.Objects, Object{File: doc.file, Line: doc.line, Name: name})
+			if err := l.validateConfig(utils.ClusterNetworkToNetworkAttachmentDefinition(obj)); err != nil {
+				result.Findings = append(result.Findings, Finding{File: doc.file, Line: doc.line, Object: name, Rule: RuleInvalidConfig, Message: err.Error()})
+			}
+		default:
+			meta, template := podTemplate(doc.obj)
+			if template == nil {
+				continue
+			}
+			namespace := l.namespace(meta)
+			name := fmt.Sprintf("%s/%s/%s", doc.obj.GetObjectKind().GroupVersionKind().Kind, namespace, meta.Name)
+			result.Objects = append(result.Objects, Object{File: doc.file, Line: doc.line, Name: name})
+
+			annotation := template.Annotations[v1.NetworkAttachmentAnnot]
+			if annotation == "" {
+				continue
+			}
+			selections, err := utils.ParseNetworkAnnotation(annotation, namespace)
+			if err != nil {
+				result.Findings = append(result.Findings, Finding{File: doc.file, Line: doc.line, Object: name, Rule: RuleInvalidAnnotation, Message: err.Error()})
+				continue
+			}
+			for _, sel := range selections {
+				if sel.Scope == v1.NetworkScopeCluster {
+					if !clusterNetworks[sel.Name] {
+						result.Findings = append(result.Findings, Finding{File: doc.file, Line: doc.line, Object: name, Rule: RuleMissingNetwork,
+							Message: fmt.Sprintf("cluster network %s is not defined", sel.Name)})
+					}
+					continue
+				}
+				if !networks[sel.Namespace+"/"+sel.Name] && !clusterNetworks[sel.Name] {
+					result.Findings = append(result.Findings, Finding{File: doc.file, Line: doc.line, Object: name, Rule: RuleMissingNetwork,
+						Message: fmt.Sprintf("network %s/%s is not defined", sel.Namespace, sel.Name)})
+				}
+			}
+		}
+	}
+	sort.SliceStable(result.Findings, func(i, j int) bool {
+		if result.Findings[i].File != result.Findings[j].File {
+			return result.Findings[i].File < result.Findings[j].File
+		}
+		return result.Findings[i].Line < result.Findings[j].Line
+	})
+	return result, nil
+}
+
+func (l *Linter) namespace(meta *metav1.ObjectMeta) string {
+	if meta.Namespace != "" {
+		return meta.Namespace
+	}
+	return l.DefaultNamespace
+}
+
+func (l *Linter) validateConfig(nad *v1.NetworkAttachmentDefinition) error {
+	if nad.Spec.Config == "" {
+		return nil
+	}
+	if _, err := utils.GetCNIConfigFromSpec(nad.Spec.Config, nad.Name); err != nil {
+		return err
+	}
+	return l.Registry.Validate(nad)
+}
+
+// podTemplate returns the metadata of the object and of the pod template of
+// the workloads, or nil for other objects
+func podTemplate(obj runtime.Object) (*metav1.ObjectMeta, *metav1.ObjectMeta) {
+	switch o := obj.(type) {
+	case *corev1.Pod:
+		return &o.ObjectMeta, &o.ObjectMeta
+	case *corev1.PodTemplate:
+		return &o.ObjectMeta, &o.Template.ObjectMeta
+	case *corev1.ReplicationController:
+		if o.Spec.Template == nil {
+			return nil, nil
+		}
+		return &o.ObjectMeta, &o.Spec.Template.ObjectMeta
+	case *appsv1.Deployment:
+		return &o.ObjectMeta, &o.Spec.Template.ObjectMeta
+	case *appsv1.StatefulSet:
+		return &o.ObjectMeta, &o.Spec.Template.ObjectMeta
+	case *appsv1.DaemonSet:
+		return &o.ObjectMeta, &o.Spec.Template.ObjectMeta
+	case *appsv1.ReplicaSet:
+		return &o.ObjectMeta, &o.Spec.Template.ObjectMeta
+	case *batchv1.Job:
+		return &o.ObjectMeta, &o.Spec.Template.ObjectMeta
+	case *batchv1.CronJob:
+		return &o.ObjectMeta, &o.Spec.JobTemplate.Spec.Template.ObjectMeta
+	}
+	return nil, nil
+}
+
+// collectFiles returns the files and the YAML and JSON files of the
+// directories, sorted
+func collectFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			switch strings.ToLower(filepath.Ext(p)) {
+			case ".yaml", ".yml", ".json":
+				if !d.IsDir() {
+					files = append(files, p)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// readDocuments decodes the documents of a file. Objects of kinds that are
+// not registered are skipped; other decoding errors are findings.
+func readDocuments(file string) ([]document, []Finding, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var docs []document
+	var findings []Finding
+	for _, raw := range splitDocuments(data) {
+		if len(bytes.TrimSpace(stripComments(raw.data))) == 0 {
+			continue
+		}
+		typeMeta := metav1.TypeMeta{}
+		if err := yaml.Unmarshal(raw.data, &typeMeta); err != nil {
+			findings = append(findings, Finding{File: file, Line: raw.line, Rule: RuleDecode, Message: err.Error()})
+			continue
+		}
+		if typeMeta.APIVersion == "" || typeMeta.Kind == "" {
+			findings = append(findings, Finding{File: file, Line: raw.line, Rule: RuleDecode, Message: "apiVersion and kind are required"})
+			continue
+		}
+		obj, _, err := deserializer.Decode(raw.data, nil, nil)
+		if runtime.IsNotRegisteredError(err) {
+			continue
+		}
+		if err != nil {
+			findings = append(findings, Finding{File: file, Line: raw.line, Rule: RuleDecode, Message: err.Error()})
+			continue
+		}
+		if obj.GetObjectKind().GroupVersionKind().Version != v1.SchemeGroupVersion.Version &&
+			obj.GetObjectKind().GroupVersionKind().Group == v1.SchemeGroupVersion.Group {
+			// lint other NetworkAttachmentDefinition versions as v1
+			gvk := obj.GetObjectKind().GroupVersionKind()
+			converted, err := scheme.ConvertToVersion(obj, v1.SchemeGroupVersion)
+			if err != nil {
+				findings = append(findings, Finding{File: file, Line: raw.line, Rule: RuleDecode, Message: fmt.Sprintf("failed to convert %s: %v", gvk, err)})
+				continue
+			}
+			obj = converted
+		}
+		docs = append(docs, document{file: file, line: raw.line, obj: obj})
+	}
+	return docs, findings, nil
+}
+
+type rawDocument struct {
+	line int
+	data []byte
+}
+
+// splitDocuments splits a YAML stream on "---" lines, keeping the line each
+// document starts on
+func splitDocuments(data []byte) []rawDocument {
+	var docs []rawDocument
+	current := rawDocument{line: 1}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(data)+1)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if documentSeparator.Match(scanner.Bytes()) {
+			docs = append(docs, current)
+			current = rawDocument{line: line + 1}
+			continue
+		}
+		current.data = append(current.data, scanner.Bytes()...)
+		current.data = append(current.data, '\n')
+	}
+	return append(docs, current)
+}
+
+// stripComments drops YAML comment lines, so documents holding only comments
+// are skipped
+func stripComments(data []byte) []byte {
+	var out []byte
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		if !bytes.HasPrefix(bytes.TrimSpace(line), []byte("#")) {
+			out = append(out, line...)
+			out = append(out, '\n')
+		}
+	}
+	return out
+}
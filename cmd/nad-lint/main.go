@@ -0,0 +1,65 @@
+// nad-lint checks manifests before they are applied: the CNI configuration of
+// every NetworkAttachmentDefinition, and the k8s.v1.cni.cncf.io/networks
+// annotation of every pod template, whose networks must be defined in the
+// same input. It exits with 1 when problems are found and 2 on usage errors.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/validation"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes the command line and returns the exit code
+func run(args []string, stdout, stderr io.Writer) int {
+	formats := make([]string, 0, len(reporters))
+	for name := range reporters {
+		formats = append(formats, name)
+	}
+	sort.Strings(formats)
+
+	fs := flag.NewFlagSet("nad-lint", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	format := fs.String("format", "text", "Report format: "+strings.Join(formats, ", ")+".")
+	namespace := fs.String("namespace", "default", "The namespace of objects without one.")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: nad-lint [-format text|sarif|junit] [-namespace ns] <file or directory>...")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+	report, ok := reporters[*format]
+	if !ok {
+		fmt.Fprintf(stderr, "error: unknown format %q\n", *format)
+		return 2
+	}
+
+	linter := &Linter{DefaultNamespace: *namespace, Registry: validation.NewDefaultRegistry()}
+	result, err := linter.Lint(fs.Args())
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 2
+	}
+	if err := report(stdout, result); err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 2
+	}
+	if len(result.Findings) > 0 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const networks = `# networks of the app
+apiVersion: k8s.cni.cncf.io/v1
+kind: NetworkAttachmentDefinition
+metadata:
+  name: bridge-net
+spec:
+  config: '{"cniVersion": "1.0.0", "type": "bridge", "ipam": {"type": "host-local", "subnet": "10.1.0.0/24"}}'
+---
+apiVersion: k8s.cni.cncf.io/v2alpha1
+kind: NetworkAttachmentDefinition
+metadata:
+  name: macvlan-net
+  namespace: infra
+spec:
+  cniVersion: 1.0.0
+  plugins:
+  - type: macvlan
+    mode: bridge
+---
+apiVersion: k8s.cni.cncf.io/v1
+kind: ClusterNetworkAttachmentDefinition
+metadata:
+  name: shared
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: ignored
+`
+
+const workloads = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  selector:
+    matchLabels:
+      app: web
+  template:
+    metadata:
+      labels:
+        app: web
+      annotations:
+        k8s.v1.cni.cncf.io/networks: bridge-net, infra/macvlan-net, cluster:shared
+    spec:
+      containers:
+      - name: web
+        image: web
+---
+apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: backup
+  namespace: tenant
+spec:
+  schedule: "@daily"
+  jobTemplate:
+    spec:
+      template:
+        metadata:
+          annotations:
+            k8s.v1.cni.cncf.io/networks: default/bridge-net, shared
+        spec:
+          containers:
+          - name: backup
+            image: backup
+`
+
+const broken = `apiVersion: k8s.cni.cncf.io/v1
+kind: NetworkAttachmentDefinition
+metadata:
+  name: vlan-net
+spec:
+  config: '{"cniVersion": "1.0.0", "type": "vlan", "master": "eth0", "vlanId": 5000}'
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: bad
+  annotations:
+    k8s.v1.cni.cncf.io/networks: '[{"name": "bridge-net"'
+---
+kind: NetworkAttachmentDefinition
+metadata:
+  name: no-version
+`
+
+func writeFiles(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func runLint(t *testing.T, args ...string) (string, string, int) {
+	var stdout, stderr bytes.Buffer
+	code := run(args, &stdout, &stderr)
+	return stdout.String(), stderr.String(), code
+}
+
+func TestLintValid(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"networks.yaml": networks, "workloads.yml": workloads, "README.md": "not a manifest"})
+
+	out, stderr, code := runLint(t, dir)
+	if code != 0 {
+		t.Fatalf("unexpected exit code %d: %s%s", code, out, stderr)
+	}
+	if !strings.Contains(out, "5 objects checked, 0 problems found") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+}
+
+func TestLintFindings(t *testing.T) {
+	// the networks are not part of the input
+	dir := writeFiles(t, map[string]string{"workloads.yaml": workloads})
+	out, _, code := runLint(t, "-namespace", "other", dir)
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d:\n%s", code, out)
+	}
+	for _, expected := range []string{
+		filepath.Join(dir, "workloads.yaml") + ":1: Deployment/other/web: network other/bridge-net is not defined [missing-network]",
+		"workloads.yaml:1: Deployment/other/web: cluster network shared is not defined [missing-network]",
+		"workloads.yaml:20: CronJob/tenant/backup: network tenant/shared is not defined [missing-network]",
+		"2 objects checked, 5 problems found",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("expected %q in output:\n%s", expected, out)
+		}
+	}
+
+	dir = writeFiles(t, map[string]string{"broken.yaml": broken})
+	out, _, code = runLint(t, filepath.Join(dir, "broken.yaml"))
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d:\n%s", code, out)
+	}
+	for _, expected := range []string{
+		"broken.yaml:1: NetworkAttachmentDefinition/default/vlan-net: vlan: vlanId 5000 is out of range 0-4094 [invalid-config]",
+		"broken.yaml:8: Pod/default/bad: parsePodNetworkAnnotation: failed to parse pod Network Attachment Selection Annotation JSON format",
+		"broken.yaml:15: apiVersion and kind are required [decode]",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("expected %q in output:\n%s", expected, out)
+		}
+	}
+}
+
+func TestSARIF(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"broken.yaml": broken})
+
+	out, _, code := runLint(t, "-format", "sarif", dir)
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+	log := sarifLog{}
+	if err := json.Unmarshal([]byte(out), &log); err != nil {
+		t.Fatal(err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 || len(log.Runs[0].Tool.Driver.Rules) != len(ruleDescriptions) {
+		t.Fatalf("unexpected log %+v", log)
+	}
+	results := log.Runs[0].Results
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %+v", results)
+	}
+	if results[1].RuleID != RuleInvalidAnnotation || results[1].Locations[0].PhysicalLocation.Region.StartLine != 8 {
+		t.Errorf("unexpected result %+v", results[1])
+	}
+}
+
+func TestJUnit(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"broken.yaml": broken, "networks.yaml": networks})
+
+	out, _, code := runLint(t, "-format", "junit", dir)
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+	suites := junitTestSuites{}
+	if err := xml.Unmarshal([]byte(out), &suites); err != nil {
+		t.Fatal(err)
+	}
+	suite := suites.Suites[0]
+	if suite.Tests != 6 || suite.Failures != 3 {
+		t.Errorf("expected 6 tests with 3 failures, got %d with %d:\n%s", suite.Tests, suite.Failures, out)
+	}
+	for _, tc := range suite.Cases {
+		if tc.Name == "NetworkAttachmentDefinition/infra/macvlan-net" && tc.Failure != nil {
+			t.Errorf("unexpected failure %+v", tc.Failure)
+		}
+	}
+}
+
+func TestUsage(t *testing.T) {
+	if _, _, code := runLint(t); code != 2 {
+		t.Errorf("expected exit code 2 without files, got %d", code)
+	}
+	if _, stderr, code := runLint(t, "-format", "html", "."); code != 2 || !strings.Contains(stderr, "unknown format") {
+		t.Errorf("expected unknown format, got %d: %s", code, stderr)
+	}
+	if _, _, code := runLint(t, "missing.yaml"); code != 2 {
+		t.Errorf("expected exit code 2 for a missing file, got %d", code)
+	}
+}
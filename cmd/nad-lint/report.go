@@ -0,0 +1,214 @@
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// reporters write a lint result in the supported formats
+var reporters = map[string]func(w io.Writer, result *Result) error{
+	"text":  writeText,
+	"sarif": writeSARIF,
+	"junit": writeJUnit,
+}
+
+func location(file string, line int) string {
+	if line == 0 {
+		return file
+	}
+	return fmt.Sprintf("%s:%d", file, line)
+}
+
+func writeText(w io.Writer, result *Result) error {
+	for _, f := range result.Findings {
+		object := ""
+		if f.Object != "" {
+			object = " " + f.Object + ":"
+		}
+		if _, err := fmt.Fprintf(w, "%s:%s %s [%s]\n", location(f.File, f.Line), object, f.Message, f.Rule); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "%d objects checked, %d problems found\n", len(result.Objects), len(result.Findings))
+	return err
+}
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string      `json:"name"`
+	Rules []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+func writeSARIF(w io.Writer, result *Result) error {
+	rules := make([]sarifRule, 0, len(ruleDescriptions))
+	for id, description := range ruleDescriptions {
+		rules = append(rules, sarifRule{ID: id, ShortDescription: sarifMessage{Text: description}})
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].ID < rules[j].ID })
+
+	results := make([]sarifResult, 0, len(result.Findings))
+	for _, f := range result.Findings {
+		message := f.Message
+		if f.Object != "" {
+			message = f.Object + ": " + message
+		}
+		loc := sarifLocation{PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(f.File)}}}
+		if f.Line > 0 {
+			loc.PhysicalLocation.Region = &sarifRegion{StartLine: f.Line}
+		}
+		results = append(results, sarifResult{
+			RuleID:    f.Rule,
+			Level:     "error",
+			Message:   sarifMessage{Text: message},
+			Locations: []sarifLocation{loc},
+		})
+	}
+
+	log := sarifLog{
+		Version: "2.1.0",
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Runs: []sarifRun{{
+			Tool:    sarifTool{Driver: sarifDriver{Name: "nad-lint", Rules: rules}},
+			Results: results,
+		}},
+	}
+	data, err := json.MarshalIndent(log, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", data)
+	return err
+}
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Type    string `xml:"type,attr"`
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// writeJUnit reports a test case per checked object, failed when it has
+// findings, and a test case per document that could not be decoded
+func writeJUnit(w io.Writer, result *Result) error {
+	type key struct {
+		file   string
+		line   int
+		object string
+	}
+	byObject := map[key][]Finding{}
+	for _, f := range result.Findings {
+		k := key{f.File, f.Line, f.Object}
+		byObject[k] = append(byObject[k], f)
+	}
+
+	suite := junitTestSuite{Name: "nad-lint"}
+	addCase := func(name, file string, findings []Finding) {
+		tc := junitTestCase{Name: name, ClassName: file}
+		if len(findings) > 0 {
+			messages := make([]string, 0, len(findings))
+			for _, f := range findings {
+				messages = append(messages, fmt.Sprintf("[%s] %s", f.Rule, f.Message))
+			}
+			tc.Failure = &junitFailure{Type: findings[0].Rule, Message: findings[0].Message, Text: strings.Join(messages, "\n")}
+			suite.Failures++
+		}
+		suite.Cases = append(suite.Cases, tc)
+		suite.Tests++
+	}
+
+	for _, obj := range result.Objects {
+		k := key{obj.File, obj.Line, obj.Name}
+		addCase(obj.Name, obj.File, byObject[k])
+		delete(byObject, k)
+	}
+	// findings that are not about a checked object, like decoding errors
+	var rest []key
+	for k := range byObject {
+		rest = append(rest, k)
+	}
+	sort.Slice(rest, func(i, j int) bool {
+		if rest[i].file != rest[j].file {
+			return rest[i].file < rest[j].file
+		}
+		return rest[i].line < rest[j].line
+	})
+	for _, k := range rest {
+		addCase(location(k.file, k.line), k.file, byObject[k])
+	}
+
+	data, err := xml.MarshalIndent(junitTestSuites{Suites: []junitTestSuite{suite}}, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s%s\n", xml.Header, data)
+	return err
+}
@@ -0,0 +1,95 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func addDefaultingFuncs(scheme *runtime.Scheme) error {
+	return RegisterDefaults(scheme)
+}
+
+// SetDefaults_NetworkAttachmentDefinition sets the CNI network name in the
+// config to the object name when it is missing, as GetCNIConfigFromSpec does
+// when the config is read, and normalizes its cniVersion
+func SetDefaults_NetworkAttachmentDefinition(obj *NetworkAttachmentDefinition) {
+	obj.Spec.Config = defaultConfig(obj.Spec.Config, obj.Name)
+}
+
+// SetDefaults_ClusterNetworkAttachmentDefinition applies the
+// NetworkAttachmentDefinition defaults to the cluster network
+func SetDefaults_ClusterNetworkAttachmentDefinition(obj *ClusterNetworkAttachmentDefinition) {
+	obj.Spec.Config = defaultConfig(obj.Spec.Config, obj.Name)
+}
+
+// SetNetworkSelectionElementDefaults sets the namespace of namespaced network
+// references without one to the namespace of the pod. Selection elements are
+// not API objects, so this is not registered in the scheme.
+func SetNetworkSelectionElementDefaults(obj *NetworkSelectionElement, podNamespace string) {
+	if obj.Namespace == "" && obj.Scope == "" {
+		obj.Namespace = podNamespace
+	}
+}
+
+// defaultConfig returns the config with the defaults applied. Configs that
+// are not JSON objects are returned unchanged and left to validation.
+func defaultConfig(config, name string) string {
+	if config == "" {
+		return config
+	}
+
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal([]byte(config), &fields); err != nil {
+		return config
+	}
+
+	changed := false
+	if name != "" {
+		var current string
+		if raw, ok := fields["name"]; !ok || (json.Unmarshal(raw, &current) == nil && current == "") {
+			fields["name"], _ = json.Marshal(name)
+			changed = true
+		}
+	}
+	if raw, ok := fields["cniVersion"]; ok {
+		var cniVersion string
+		if json.Unmarshal(raw, &cniVersion) == nil {
+			if normalized, ok := normalizeCNIVersion(cniVersion); ok && normalized != cniVersion {
+				fields["cniVersion"], _ = json.Marshal(normalized)
+				changed = true
+			}
+		}
+	}
+	if !changed {
+		return config
+	}
+
+	defaulted, err := json.Marshal(fields)
+	if err != nil {
+		return config
+	}
+	return string(defaulted)
+}
+
+// normalizeCNIVersion returns the version as major.minor.micro, so " v1.0"
+// becomes "1.0.0". It returns false for versions it cannot parse.
+func normalizeCNIVersion(version string) (string, bool) {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	parts := strings.Split(version, ".")
+	if len(parts) > 3 {
+		return "", false
+	}
+	numbers := [3]int{}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return "", false
+		}
+		numbers[i] = n
+	}
+	return fmt.Sprintf("%d.%d.%d", numbers[0], numbers[1], numbers[2]), true
+}
@@ -0,0 +1,105 @@
+package v1
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestNetworkAttachmentDefinitionDefaults(t *testing.T) {
+	testCases := []struct {
+		description    string
+		name           string
+		config         string
+		expectedConfig string
+	}{
+		{
+			description:    "empty config",
+			name:           "net1",
+			config:         "",
+			expectedConfig: "",
+		},
+		{
+			description:    "config with name and normalized version is unchanged",
+			name:           "net1",
+			config:         `{ "cniVersion": "1.0.0", "name": "other", "type": "bridge" }`,
+			expectedConfig: `{ "cniVersion": "1.0.0", "name": "other", "type": "bridge" }`,
+		},
+		{
+			description:    "missing name",
+			name:           "net1",
+			config:         `{"cniVersion": "0.3.1", "type": "bridge"}`,
+			expectedConfig: `{"cniVersion":"0.3.1","name":"net1","type":"bridge"}`,
+		},
+		{
+			description:    "empty name",
+			name:           "net1",
+			config:         `{"cniVersion": "0.3.1", "name": "", "type": "bridge"}`,
+			expectedConfig: `{"cniVersion":"0.3.1","name":"net1","type":"bridge"}`,
+		},
+		{
+			description:    "short cniVersion",
+			name:           "net1",
+			config:         `{"cniVersion": " v1.0", "name": "net1", "plugins": [{"type": "bridge"}]}`,
+			expectedConfig: `{"cniVersion":"1.0.0","name":"net1","plugins":[{"type":"bridge"}]}`,
+		},
+		{
+			description:    "unparsable cniVersion is left to validation",
+			name:           "net1",
+			config:         `{"cniVersion": "latest", "name": "net1", "type": "bridge"}`,
+			expectedConfig: `{"cniVersion": "latest", "name": "net1", "type": "bridge"}`,
+		},
+		{
+			description:    "invalid config is left to validation",
+			name:           "net1",
+			config:         `{"cniVersion": `,
+			expectedConfig: `{"cniVersion": `,
+		},
+	}
+
+	scheme := runtime.NewScheme()
+	if err := AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range testCases {
+		nad := &NetworkAttachmentDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: tc.name, Namespace: "default"},
+			Spec:       NetworkAttachmentDefinitionSpec{Config: tc.config},
+		}
+		scheme.Default(nad)
+		if nad.Spec.Config != tc.expectedConfig {
+			t.Errorf("%s: expected config %s, got %s", tc.description, tc.expectedConfig, nad.Spec.Config)
+		}
+
+		cnet := &ClusterNetworkAttachmentDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: tc.name},
+			Spec:       NetworkAttachmentDefinitionSpec{Config: tc.config},
+		}
+		scheme.Default(cnet)
+		if cnet.Spec.Config != tc.expectedConfig {
+			t.Errorf("%s: expected cluster network config %s, got %s", tc.description, tc.expectedConfig, cnet.Spec.Config)
+		}
+	}
+}
+
+func TestNetworkSelectionElementDefaults(t *testing.T) {
+	sel := &NetworkSelectionElement{Name: "net1"}
+	SetNetworkSelectionElementDefaults(sel, "tenant")
+	if sel.Namespace != "tenant" {
+		t.Errorf("expected namespace tenant, got %q", sel.Namespace)
+	}
+
+	sel = &NetworkSelectionElement{Name: "net1", Namespace: "infra"}
+	SetNetworkSelectionElementDefaults(sel, "tenant")
+	if sel.Namespace != "infra" {
+		t.Errorf("expected namespace infra, got %q", sel.Namespace)
+	}
+
+	sel = &NetworkSelectionElement{Name: "net1", Scope: NetworkScopeCluster}
+	SetNetworkSelectionElementDefaults(sel, "tenant")
+	if sel.Namespace != "" {
+		t.Errorf("expected no namespace for cluster network, got %q", sel.Namespace)
+	}
+}
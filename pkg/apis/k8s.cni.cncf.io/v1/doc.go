@@ -1,4 +1,5 @@
 // +k8s:deepcopy-gen=package,register
+// +k8s:defaulter-gen=TypeMeta
 // +groupName=k8s.cni.cncf.io
 // +groupGoName=K8sCniCncfIo
 
@@ -31,6 +31,29 @@ type NetworkAttachmentDefinitionList struct {
 	Items []NetworkAttachmentDefinition `json:"items"`
 }
 
+// +genclient
+// +genclient:nonNamespaced
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +resourceName=cluster-network-attachment-definitions
+
+// ClusterNetworkAttachmentDefinition is a cluster-scoped network definition
+// that pods in any namespace can reference
+type ClusterNetworkAttachmentDefinition struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec NetworkAttachmentDefinitionSpec `json:"spec"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type ClusterNetworkAttachmentDefinitionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []ClusterNetworkAttachmentDefinition `json:"items"`
+}
+
 // DNS contains values interesting for DNS resolvers
 // +k8s:deepcopy-gen=false
 type DNS struct {
@@ -168,6 +191,11 @@ type NetworkSelectionElement struct {
 	// IPAMClaimReference container the IPAMClaim name where the IPs for this
 	// attachment will be located.
 	IPAMClaimReference string `json:"ipam-claim-reference,omitempty"`
+	// Scope is set to NetworkScopeCluster when Name refers to a
+	// ClusterNetworkAttachmentDefinition instead of a namespaced network.
+	// It extends the annotation format of the CRD specification: clients
+	// unaware of it ignore the field and look Name up as a namespaced network
+	Scope string `json:"scope,omitempty"`
 }
 
 func (nse *NetworkSelectionElement) UnmarshalJSON(b []byte) error {
@@ -181,11 +209,60 @@ func (nse *NetworkSelectionElement) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+// Scopes of the networks referenced by a NetworkSelectionElement
+const (
+	// NetworkScopeCluster selects a ClusterNetworkAttachmentDefinition
+	NetworkScopeCluster = "cluster"
+	// ClusterNetworkPrefix marks a cluster network reference in the
+	// comma-delimited annotation format, e.g. "cluster:<name>@<ifname>"
+	ClusterNetworkPrefix = NetworkScopeCluster + ":"
+)
+
 const (
 	// Pod annotation for network-attachment-definition
 	NetworkAttachmentAnnot = "k8s.v1.cni.cncf.io/networks"
 	// Pod annotation for network status
 	NetworkStatusAnnot = "k8s.v1.cni.cncf.io/network-status"
+	// Network annotation for the device plugin resource backing the network
+	ResourceNameAnnot = "k8s.v1.cni.cncf.io/resourceName"
+)
+
+// Annotations of the namespace access policy, which controls the pods of
+// other namespaces that may attach to a network
+const (
+	// Network annotation listing the namespaces, or "*" for all, whose pods
+	// may attach to the network from another namespace
+	AllowedNamespacesAnnot = "k8s.v1.cni.cncf.io/allowed-namespaces"
+	// Network annotation holding a label selector of the namespaces whose
+	// pods may attach to the network from another namespace
+	AllowedNamespaceSelectorAnnot = "k8s.v1.cni.cncf.io/allowed-namespace-selector"
+)
+
+// Annotations tracking the network configs of pods, so that the pods of a
+// changed network can be rolled out
+const (
+	// Pod annotation recording the config hash of every attached network,
+	// as a JSON object keyed by "<namespace>/<name>" or "cluster:<name>"
+	NetworkConfigHashesAnnot = "k8s.v1.cni.cncf.io/network-config-hashes"
+	// Deployment and StatefulSet annotation opting in to a rollout when the
+	// config of a network used by their pods changes
+	RolloutOnNetworkChangeAnnot = "k8s.v1.cni.cncf.io/rollout-on-network-change"
+	// Pod template annotation holding the network config hashes a rollout
+	// was triggered for, in the NetworkConfigHashesAnnot format
+	NetworkConfigRolloutAnnot = "k8s.v1.cni.cncf.io/network-config-rollout"
+)
+
+// Reasons of the Events recorded on pods and networks, so that all
+// implementations report the network attachment lifecycle consistently
+const (
+	// A network was attached to the pod
+	NetworkAttachedReason = "NetworkAttached"
+	// A network failed to attach to the pod
+	NetworkAttachFailedReason = "NetworkAttachFailed"
+	// The NetworkStatusAnnot annotation of the pod was updated
+	NetworkStatusUpdatedReason = "NetworkStatusUpdated"
+	// The NetworkAttachmentAnnot annotation of the pod is invalid
+	InvalidNetworkAnnotationReason = "InvalidNetworkAnnotation"
 )
 
 // NoK8sNetworkError indicates error, no network in kubernetes
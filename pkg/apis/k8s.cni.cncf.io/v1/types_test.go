@@ -63,3 +63,44 @@ func run(tc testCase) error {
 	}
 	return nil
 }
+
+// oldNetworkSelectionElement is the NetworkSelectionElement of clients that
+// predate the Scope field
+type oldNetworkSelectionElement struct {
+	Name             string `json:"name"`
+	Namespace        string `json:"namespace,omitempty"`
+	InterfaceRequest string `json:"interface,omitempty"`
+}
+
+func TestNetworkSelectionElementScopeIgnoredByOldClients(t *testing.T) {
+	input, err := json.Marshal(NetworkSelectionElement{
+		Name:             "net1",
+		InterfaceRequest: "eth1",
+		Scope:            NetworkScopeCluster,
+	})
+	if err != nil {
+		t.Fatalf("failed to marshal the selection: %v", err)
+	}
+
+	var old oldNetworkSelectionElement
+	if err := json.Unmarshal(input, &old); err != nil {
+		t.Fatalf("old client failed to unmarshal %s: %v", input, err)
+	}
+	expectedOld := oldNetworkSelectionElement{Name: "net1", InterfaceRequest: "eth1"}
+	if old != expectedOld {
+		t.Errorf("old client parsed %+v. Expected %+v", old, expectedOld)
+	}
+
+	output, err := json.Marshal(old)
+	if err != nil {
+		t.Fatalf("old client failed to marshal the selection: %v", err)
+	}
+	var nse NetworkSelectionElement
+	if err := json.Unmarshal(output, &nse); err != nil {
+		t.Fatalf("failed to unmarshal %s: %v", output, err)
+	}
+	expected := NetworkSelectionElement{Name: "net1", InterfaceRequest: "eth1"}
+	if !reflect.DeepEqual(nse, expected) {
+		t.Errorf("parsed object is wrong: %+v. Expected object: %+v", nse, expected)
+	}
+}
@@ -0,0 +1,368 @@
+package v2alpha1
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/conversion"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	v1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+)
+
+const (
+	// DescriptionAnnot holds Spec.Description on v1 objects
+	DescriptionAnnot = "k8s.v1.cni.cncf.io/description"
+	// TagsAnnot holds Spec.Tags, as a JSON list, on v1 objects
+	TagsAnnot = "k8s.v1.cni.cncf.io/tags"
+	// ConfigFieldsAnnot holds, as a JSON object, the top level fields of the
+	// v1 config the spec has no field for, such as disableCheck, on v2alpha1
+	// objects
+	ConfigFieldsAnnot = "k8s.v1.cni.cncf.io/config-fields"
+	// SinglePluginAnnot is set to "true" on v2alpha1 objects converted from
+	// a single plugin v1 config, which is rendered back as such
+	SinglePluginAnnot = "k8s.v1.cni.cncf.io/single-plugin"
+	// OriginalConfigAnnot holds the v1 config on v2alpha1 objects when it is
+	// not a valid config and cannot be represented by the spec
+	OriginalConfigAnnot = "k8s.v1.cni.cncf.io/original-config"
+)
+
+func addConversionFuncs(scheme *runtime.Scheme) error {
+	if err := scheme.AddConversionFunc((*v1.NetworkAttachmentDefinition)(nil), (*NetworkAttachmentDefinition)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_NetworkAttachmentDefinition_To_v2alpha1_NetworkAttachmentDefinition(a.(*v1.NetworkAttachmentDefinition), b.(*NetworkAttachmentDefinition), scope)
+	}); err != nil {
+		return err
+	}
+	if err := scheme.AddConversionFunc((*NetworkAttachmentDefinition)(nil), (*v1.NetworkAttachmentDefinition)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v2alpha1_NetworkAttachmentDefinition_To_v1_NetworkAttachmentDefinition(a.(*NetworkAttachmentDefinition), b.(*v1.NetworkAttachmentDefinition), scope)
+	}); err != nil {
+		return err
+	}
+	if err := scheme.AddConversionFunc((*v1.NetworkAttachmentDefinitionList)(nil), (*NetworkAttachmentDefinitionList)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_NetworkAttachmentDefinitionList_To_v2alpha1_NetworkAttachmentDefinitionList(a.(*v1.NetworkAttachmentDefinitionList), b.(*NetworkAttachmentDefinitionList), scope)
+	}); err != nil {
+		return err
+	}
+	return scheme.AddConversionFunc((*NetworkAttachmentDefinitionList)(nil), (*v1.NetworkAttachmentDefinitionList)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v2alpha1_NetworkAttachmentDefinitionList_To_v1_NetworkAttachmentDefinitionList(a.(*NetworkAttachmentDefinitionList), b.(*v1.NetworkAttachmentDefinitionList), scope)
+	})
+}
+
+// Convert_v1_NetworkAttachmentDefinition_To_v2alpha1_NetworkAttachmentDefinition
+// splits the v1 config into the CNI version and plugin list. The resource
+// name, description and tags are taken from their v1 annotations. What the
+// spec cannot represent is kept in annotations, so converting back to v1
+// gives an equivalent config: the other top level fields in
+// ConfigFieldsAnnot, single plugin configs with SinglePluginAnnot, and
+// configs that are not valid in OriginalConfigAnnot.
+func Convert_v1_NetworkAttachmentDefinition_To_v2alpha1_NetworkAttachmentDefinition(in *v1.NetworkAttachmentDefinition, out *NetworkAttachmentDefinition, s conversion.Scope) error {
+	out.ObjectMeta = *in.ObjectMeta.DeepCopy()
+	out.Spec = NetworkAttachmentDefinitionSpec{}
+
+	annotations := out.Annotations
+	if resourceName, ok := annotations[v1.ResourceNameAnnot]; ok {
+		out.Spec.ResourceName = resourceName
+		delete(annotations, v1.ResourceNameAnnot)
+	}
+	if description, ok := annotations[DescriptionAnnot]; ok {
+		out.Spec.Description = description
+		delete(annotations, DescriptionAnnot)
+	}
+	if tags, ok := annotations[TagsAnnot]; ok {
+		// tags that are not a JSON list stay in the annotation
+		if err := json.Unmarshal([]byte(tags), &out.Spec.Tags); err == nil {
+			delete(annotations, TagsAnnot)
+		}
+	}
+
+	delete(annotations, ConfigFieldsAnnot)
+	delete(annotations, SinglePluginAnnot)
+	delete(annotations, OriginalConfigAnnot)
+	cniVersion, plugins, fields, single, err := splitConfig(in.Spec.Config)
+	if err == nil {
+		out.Spec.CNIVersion = cniVersion
+		out.Spec.Plugins = plugins
+		// the name is only kept when it is not the object's
+		var name string
+		if json.Unmarshal(fields["name"], &name) == nil && name == in.Name {
+			delete(fields, "name")
+		}
+	}
+	if err != nil || len(fields) > 0 || single {
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+	}
+	switch {
+	case err != nil:
+		annotations[OriginalConfigAnnot] = in.Spec.Config
+	case len(fields) > 0:
+		rawFields, err := json.Marshal(fields)
+		if err != nil {
+			return err
+		}
+		annotations[ConfigFieldsAnnot] = string(rawFields)
+	}
+	if single {
+		annotations[SinglePluginAnnot] = "true"
+	}
+
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+	out.Annotations = annotations
+	return nil
+}
+
+// Convert_v2alpha1_NetworkAttachmentDefinition_To_v1_NetworkAttachmentDefinition
+// renders the spec as a compact CNI config named after the object, along
+// with the fields of the ConfigFieldsAnnot annotation, as a single plugin
+// config when SinglePluginAnnot is set and the spec has one plugin. The
+// OriginalConfigAnnot config is restored as long as the spec is empty.
+func Convert_v2alpha1_NetworkAttachmentDefinition_To_v1_NetworkAttachmentDefinition(in *NetworkAttachmentDefinition, out *v1.NetworkAttachmentDefinition, s conversion.Scope) error {
+	out.ObjectMeta = *in.ObjectMeta.DeepCopy()
+	out.Spec = v1.NetworkAttachmentDefinitionSpec{}
+
+	annotations := out.Annotations
+	var fields map[string]json.RawMessage
+	if rawFields, ok := annotations[ConfigFieldsAnnot]; ok {
+		delete(annotations, ConfigFieldsAnnot)
+		if err := json.Unmarshal([]byte(rawFields), &fields); err != nil {
+			return fmt.Errorf("invalid %s annotation: %v", ConfigFieldsAnnot, err)
+		}
+	}
+	single := annotations[SinglePluginAnnot] == "true"
+	delete(annotations, SinglePluginAnnot)
+	if original, ok := annotations[OriginalConfigAnnot]; ok {
+		delete(annotations, OriginalConfigAnnot)
+		if describesSpec(original, &in.Spec) {
+			out.Spec.Config = original
+		}
+	}
+	if out.Spec.Config == "" {
+		config, err := joinConfig(in.Name, in.Spec.CNIVersion, fields, in.Spec.Plugins, single)
+		if err != nil {
+			return err
+		}
+		out.Spec.Config = config
+	}
+
+	if in.Spec.ResourceName != "" || in.Spec.Description != "" || len(in.Spec.Tags) > 0 {
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+	}
+	if in.Spec.ResourceName != "" {
+		annotations[v1.ResourceNameAnnot] = in.Spec.ResourceName
+	}
+	if in.Spec.Description != "" {
+		annotations[DescriptionAnnot] = in.Spec.Description
+	}
+	if len(in.Spec.Tags) > 0 {
+		tags, err := json.Marshal(in.Spec.Tags)
+		if err != nil {
+			return err
+		}
+		annotations[TagsAnnot] = string(tags)
+	}
+
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+	out.Annotations = annotations
+	return nil
+}
+
+// Convert_v1_NetworkAttachmentDefinitionList_To_v2alpha1_NetworkAttachmentDefinitionList converts every item of the list
+func Convert_v1_NetworkAttachmentDefinitionList_To_v2alpha1_NetworkAttachmentDefinitionList(in *v1.NetworkAttachmentDefinitionList, out *NetworkAttachmentDefinitionList, s conversion.Scope) error {
+	out.ListMeta = in.ListMeta
+	out.Items = nil
+	if in.Items != nil {
+		out.Items = make([]NetworkAttachmentDefinition, len(in.Items))
+		for i := range in.Items {
+			if err := Convert_v1_NetworkAttachmentDefinition_To_v2alpha1_NetworkAttachmentDefinition(&in.Items[i], &out.Items[i], s); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Convert_v2alpha1_NetworkAttachmentDefinitionList_To_v1_NetworkAttachmentDefinitionList converts every item of the list
+func Convert_v2alpha1_NetworkAttachmentDefinitionList_To_v1_NetworkAttachmentDefinitionList(in *NetworkAttachmentDefinitionList, out *v1.NetworkAttachmentDefinitionList, s conversion.Scope) error {
+	out.ListMeta = in.ListMeta
+	out.Items = nil
+	if in.Items != nil {
+		out.Items = make([]v1.NetworkAttachmentDefinition, len(in.Items))
+		for i := range in.Items {
+			if err := Convert_v2alpha1_NetworkAttachmentDefinition_To_v1_NetworkAttachmentDefinition(&in.Items[i], &out.Items[i], s); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// splitConfig returns the CNI version and plugins of a v1 config, along with
+// its other top level fields. A single plugin config becomes a one element
+// plugin list, and only its name is returned as a field.
+func splitConfig(config string) (string, []runtime.RawExtension, map[string]json.RawMessage, bool, error) {
+	if config == "" {
+		return "", nil, nil, false, nil
+	}
+
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal([]byte(config), &fields); err != nil {
+		return "", nil, nil, false, fmt.Errorf("failed to unmarshal config: %v", err)
+	}
+
+	var cniVersion string
+	if raw, ok := fields["cniVersion"]; ok {
+		if err := json.Unmarshal(raw, &cniVersion); err != nil {
+			return "", nil, nil, false, fmt.Errorf("invalid cniVersion: %v", err)
+		}
+		delete(fields, "cniVersion")
+	}
+
+	if raw, ok := fields["plugins"]; ok {
+		var rawPlugins []json.RawMessage
+		if err := json.Unmarshal(raw, &rawPlugins); err != nil {
+			return "", nil, nil, false, fmt.Errorf("invalid plugins: %v", err)
+		}
+		var plugins []runtime.RawExtension
+		for _, plugin := range rawPlugins {
+			plugins = append(plugins, runtime.RawExtension{Raw: plugin})
+		}
+		delete(fields, "plugins")
+		return cniVersion, plugins, fields, false, nil
+	}
+
+	pluginFields := fields
+	fields = map[string]json.RawMessage{}
+	if name, ok := pluginFields["name"]; ok {
+		fields["name"] = name
+		delete(pluginFields, "name")
+	}
+	plugin, err := json.Marshal(pluginFields)
+	if err != nil {
+		return "", nil, nil, false, err
+	}
+	return cniVersion, []runtime.RawExtension{{Raw: plugin}}, fields, true, nil
+}
+
+// joinConfig renders a compact v1 config, as a config list unless single is
+// set and there is one plugin. The list starts with the CNI version and
+// name, followed by the other fields and the plugins.
+func joinConfig(name, cniVersion string, fields map[string]json.RawMessage, plugins []runtime.RawExtension, single bool) (string, error) {
+	if cniVersion == "" && len(plugins) == 0 && len(fields) == 0 {
+		return "", nil
+	}
+
+	top := map[string]json.RawMessage{}
+	for k, v := range fields {
+		top[k] = v
+	}
+	if cniVersion != "" {
+		top["cniVersion"], _ = json.Marshal(cniVersion)
+	}
+	if _, ok := top["name"]; !ok && name != "" {
+		top["name"], _ = json.Marshal(name)
+	}
+
+	if single && len(plugins) == 1 {
+		raw, err := pluginBytes(&plugins[0])
+		if err != nil {
+			return "", fmt.Errorf("plugin 0 is not valid JSON: %v", err)
+		}
+		plugin := map[string]json.RawMessage{}
+		if err := json.Unmarshal(raw, &plugin); err != nil {
+			return "", fmt.Errorf("plugin 0 is not a JSON object: %v", err)
+		}
+		for k, v := range top {
+			plugin[k] = v
+		}
+		config, err := json.Marshal(plugin)
+		if err != nil {
+			return "", err
+		}
+		return string(config), nil
+	}
+
+	keys := make([]string, 0, len(top))
+	for k := range top {
+		if k != "cniVersion" && k != "name" && k != "plugins" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	if _, ok := top["name"]; ok {
+		keys = append([]string{"name"}, keys...)
+	}
+	if _, ok := top["cniVersion"]; ok {
+		keys = append([]string{"cniVersion"}, keys...)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("{")
+	for _, k := range keys {
+		key, _ := json.Marshal(k)
+		buf.Write(key)
+		buf.WriteString(":")
+		if err := json.Compact(&buf, top[k]); err != nil {
+			return "", fmt.Errorf("field %s is not valid JSON: %v", k, err)
+		}
+		buf.WriteString(",")
+	}
+	buf.WriteString(`"plugins":[`)
+	for i := range plugins {
+		raw, err := pluginBytes(&plugins[i])
+		if err != nil {
+			return "", fmt.Errorf("plugin %d is not valid JSON: %v", i, err)
+		}
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		if err := json.Compact(&buf, raw); err != nil {
+			return "", fmt.Errorf("plugin %d is not valid JSON: %v", i, err)
+		}
+	}
+	buf.WriteString("]}")
+	return buf.String(), nil
+}
+
+// describesSpec checks whether the v1 config holds the same CNI version and
+// plugins as the spec
+func describesSpec(config string, spec *NetworkAttachmentDefinitionSpec) bool {
+	cniVersion, plugins, _, _, err := splitConfig(config)
+	if err != nil {
+		return spec.CNIVersion == "" && len(spec.Plugins) == 0
+	}
+	if cniVersion != spec.CNIVersion || len(plugins) != len(spec.Plugins) {
+		return false
+	}
+	for i := range plugins {
+		raw, err := pluginBytes(&spec.Plugins[i])
+		if err != nil {
+			return false
+		}
+		var a, b interface{}
+		if json.Unmarshal(plugins[i].Raw, &a) != nil || json.Unmarshal(raw, &b) != nil {
+			return false
+		}
+		if !reflect.DeepEqual(a, b) {
+			return false
+		}
+	}
+	return true
+}
+
+// pluginBytes returns the JSON of the plugin, encoding Object when Raw is unset
+func pluginBytes(plugin *runtime.RawExtension) ([]byte, error) {
+	if plugin.Raw == nil && plugin.Object != nil {
+		return json.Marshal(plugin.Object)
+	}
+	return plugin.Raw, nil
+}
@@ -0,0 +1,236 @@
+package v2alpha1
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	v1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+)
+
+func TestConversionRoundTripFromV1(t *testing.T) {
+	testCases := []struct {
+		description         string
+		config              string
+		annotations         map[string]string
+		expectedSpec        NetworkAttachmentDefinitionSpec
+		expectedAnnotations map[string]string
+	}{
+		{
+			description: "empty config with resource name",
+			annotations: map[string]string{v1.ResourceNameAnnot: "intel.com/sriov"},
+			expectedSpec: NetworkAttachmentDefinitionSpec{
+				ResourceName: "intel.com/sriov",
+			},
+		},
+		{
+			description: "config list rendered by v2alpha1",
+			config:      `{"cniVersion":"1.0.0","name":"net1","plugins":[{"type":"bridge"},{"type":"tuning"}]}`,
+			expectedSpec: NetworkAttachmentDefinitionSpec{
+				CNIVersion: "1.0.0",
+				Plugins:    []runtime.RawExtension{{Raw: []byte(`{"type":"bridge"}`)}, {Raw: []byte(`{"type":"tuning"}`)}},
+			},
+		},
+		{
+			description: "formatted config list with extra fields",
+			config:      `{ "cniVersion": "0.4.0", "name": "other", "disableCheck": true, "plugins": [ {"type": "macvlan"} ] }`,
+			expectedSpec: NetworkAttachmentDefinitionSpec{
+				CNIVersion: "0.4.0",
+				Plugins:    []runtime.RawExtension{{Raw: []byte(`{"type": "macvlan"}`)}},
+			},
+			expectedAnnotations: map[string]string{ConfigFieldsAnnot: `{"disableCheck":true,"name":"other"}`},
+		},
+		{
+			description: "single plugin config",
+			config:      `{"cniVersion": "0.3.1", "name": "net1", "type": "ipvlan", "master": "eth0"}`,
+			annotations: map[string]string{DescriptionAnnot: "storage network", TagsAnnot: `["storage","fast"]`},
+			expectedSpec: NetworkAttachmentDefinitionSpec{
+				CNIVersion:  "0.3.1",
+				Plugins:     []runtime.RawExtension{{Raw: []byte(`{"master":"eth0","type":"ipvlan"}`)}},
+				Description: "storage network",
+				Tags:        []string{"storage", "fast"},
+			},
+			expectedAnnotations: map[string]string{SinglePluginAnnot: "true"},
+		},
+		{
+			description:         "invalid config",
+			config:              `{"cniVersion": `,
+			expectedSpec:        NetworkAttachmentDefinitionSpec{},
+			expectedAnnotations: map[string]string{OriginalConfigAnnot: `{"cniVersion": `},
+		},
+	}
+
+	for _, tc := range testCases {
+		in := &v1.NetworkAttachmentDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "net1", Namespace: "default", Annotations: tc.annotations},
+			Spec:       v1.NetworkAttachmentDefinitionSpec{Config: tc.config},
+		}
+		inCopy := in.DeepCopy()
+
+		out := &NetworkAttachmentDefinition{}
+		if err := Convert_v1_NetworkAttachmentDefinition_To_v2alpha1_NetworkAttachmentDefinition(in, out, nil); err != nil {
+			t.Fatalf("%s: unexpected error converting to v2alpha1: %v", tc.description, err)
+		}
+		if !reflect.DeepEqual(in, inCopy) {
+			t.Errorf("%s: conversion modified its input", tc.description)
+		}
+		if !reflect.DeepEqual(out.Spec, tc.expectedSpec) {
+			t.Errorf("%s: expected spec %+v, got %+v", tc.description, tc.expectedSpec, out.Spec)
+		}
+		for _, annot := range []string{ConfigFieldsAnnot, SinglePluginAnnot, OriginalConfigAnnot} {
+			if out.Annotations[annot] != tc.expectedAnnotations[annot] {
+				t.Errorf("%s: expected %s annotation %q, got %q", tc.description, annot, tc.expectedAnnotations[annot], out.Annotations[annot])
+			}
+		}
+
+		back := &v1.NetworkAttachmentDefinition{}
+		if err := Convert_v2alpha1_NetworkAttachmentDefinition_To_v1_NetworkAttachmentDefinition(out, back, nil); err != nil {
+			t.Fatalf("%s: unexpected error converting to v1: %v", tc.description, err)
+		}
+		// configs are rendered compact, so they are compared as JSON
+		if !sameConfig(back.Spec.Config, in.Spec.Config) {
+			t.Errorf("%s: expected config %s, got %s", tc.description, in.Spec.Config, back.Spec.Config)
+		}
+		back.Spec.Config = in.Spec.Config
+		if !reflect.DeepEqual(back, in) {
+			t.Errorf("%s: round trip mismatch\nexpected %+v\ngot      %+v", tc.description, in, back)
+		}
+	}
+}
+
+func sameConfig(a, b string) bool {
+	if a == b {
+		return true
+	}
+	var aValue, bValue interface{}
+	if json.Unmarshal([]byte(a), &aValue) != nil || json.Unmarshal([]byte(b), &bValue) != nil {
+		return false
+	}
+	return reflect.DeepEqual(aValue, bValue)
+}
+
+func TestConversionKeepsConfigFieldsOnEdit(t *testing.T) {
+	in := &v1.NetworkAttachmentDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "net1"},
+		Spec:       v1.NetworkAttachmentDefinitionSpec{Config: `{"cniVersion": "1.1.0", "name": "net1", "disableGC": true, "type": "macvlan", "master": "eth0"}`},
+	}
+	out := &NetworkAttachmentDefinition{}
+	if err := Convert_v1_NetworkAttachmentDefinition_To_v2alpha1_NetworkAttachmentDefinition(in, out, nil); err != nil {
+		t.Fatalf("unexpected error converting to v2alpha1: %v", err)
+	}
+
+	// the edited plugin is used, as a single plugin config
+	out.Spec.Plugins[0].Raw = []byte(`{"disableGC":true,"master":"eth1","type":"macvlan"}`)
+	back := &v1.NetworkAttachmentDefinition{}
+	if err := Convert_v2alpha1_NetworkAttachmentDefinition_To_v1_NetworkAttachmentDefinition(out, back, nil); err != nil {
+		t.Fatalf("unexpected error converting to v1: %v", err)
+	}
+	expectedConfig := `{"cniVersion":"1.1.0","disableGC":true,"master":"eth1","name":"net1","type":"macvlan"}`
+	if back.Spec.Config != expectedConfig {
+		t.Errorf("expected config %s, got %s", expectedConfig, back.Spec.Config)
+	}
+
+	// fields the spec cannot represent are kept when plugins are added
+	in.Spec.Config = `{"cniVersion": "1.1.0", "name": "net1", "disableGC": true, "plugins": [{"type": "macvlan"}]}`
+	if err := Convert_v1_NetworkAttachmentDefinition_To_v2alpha1_NetworkAttachmentDefinition(in, out, nil); err != nil {
+		t.Fatalf("unexpected error converting to v2alpha1: %v", err)
+	}
+	out.Spec.Plugins = append(out.Spec.Plugins, runtime.RawExtension{Raw: []byte(`{"type":"tuning"}`)})
+	if err := Convert_v2alpha1_NetworkAttachmentDefinition_To_v1_NetworkAttachmentDefinition(out, back, nil); err != nil {
+		t.Fatalf("unexpected error converting to v1: %v", err)
+	}
+	expectedConfig = `{"cniVersion":"1.1.0","name":"net1","disableGC":true,"plugins":[{"type":"macvlan"},{"type":"tuning"}]}`
+	if back.Spec.Config != expectedConfig {
+		t.Errorf("expected config %s, got %s", expectedConfig, back.Spec.Config)
+	}
+	if back.Annotations != nil {
+		t.Errorf("expected no annotations, got %v", back.Annotations)
+	}
+}
+
+func TestConversionRoundTripFromV2alpha1(t *testing.T) {
+	in := &NetworkAttachmentDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "net1", Namespace: "default", Labels: map[string]string{"app": "x"}},
+		Spec: NetworkAttachmentDefinitionSpec{
+			CNIVersion:   "1.0.0",
+			Plugins:      []runtime.RawExtension{{Raw: []byte(`{"type":"bridge","bridge":"br0"}`)}},
+			ResourceName: "example.com/nic",
+			Description:  "a bridge",
+			Tags:         []string{"lab"},
+		},
+	}
+
+	out := &v1.NetworkAttachmentDefinition{}
+	if err := Convert_v2alpha1_NetworkAttachmentDefinition_To_v1_NetworkAttachmentDefinition(in, out, nil); err != nil {
+		t.Fatalf("unexpected error converting to v1: %v", err)
+	}
+	expectedConfig := `{"cniVersion":"1.0.0","name":"net1","plugins":[{"type":"bridge","bridge":"br0"}]}`
+	if out.Spec.Config != expectedConfig {
+		t.Errorf("expected config %s, got %s", expectedConfig, out.Spec.Config)
+	}
+	if out.Annotations[v1.ResourceNameAnnot] != "example.com/nic" {
+		t.Errorf("expected resource name annotation, got %v", out.Annotations)
+	}
+
+	back := &NetworkAttachmentDefinition{}
+	if err := Convert_v1_NetworkAttachmentDefinition_To_v2alpha1_NetworkAttachmentDefinition(out, back, nil); err != nil {
+		t.Fatalf("unexpected error converting to v2alpha1: %v", err)
+	}
+	if !reflect.DeepEqual(back, in) {
+		t.Errorf("round trip mismatch\nexpected %+v\ngot      %+v", in, back)
+	}
+}
+
+func TestConversionDropsStaleOriginalConfig(t *testing.T) {
+	in := &NetworkAttachmentDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "net1",
+			Annotations: map[string]string{OriginalConfigAnnot: `{"cniVersion": "0.4.0", "type": "macvlan"}`},
+		},
+		Spec: NetworkAttachmentDefinitionSpec{
+			CNIVersion: "0.4.0",
+			Plugins:    []runtime.RawExtension{{Raw: []byte(`{"type":"ipvlan"}`)}},
+		},
+	}
+
+	out := &v1.NetworkAttachmentDefinition{}
+	if err := Convert_v2alpha1_NetworkAttachmentDefinition_To_v1_NetworkAttachmentDefinition(in, out, nil); err != nil {
+		t.Fatalf("unexpected error converting to v1: %v", err)
+	}
+	expectedConfig := `{"cniVersion":"0.4.0","name":"net1","plugins":[{"type":"ipvlan"}]}`
+	if out.Spec.Config != expectedConfig {
+		t.Errorf("expected config %s, got %s", expectedConfig, out.Spec.Config)
+	}
+	if out.Annotations != nil {
+		t.Errorf("expected no annotations, got %v", out.Annotations)
+	}
+}
+
+func TestSchemeConversion(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := v1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	in := &v1.NetworkAttachmentDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "net1"},
+		Spec:       v1.NetworkAttachmentDefinitionSpec{Config: `{"cniVersion":"1.0.0","name":"net1","plugins":[{"type":"bridge"}]}`},
+	}
+	obj, err := scheme.ConvertToVersion(in, SchemeGroupVersion)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out, ok := obj.(*NetworkAttachmentDefinition)
+	if !ok {
+		t.Fatalf("expected a v2alpha1 NetworkAttachmentDefinition, got %T", obj)
+	}
+	if out.APIVersion != SchemeGroupVersion.String() || len(out.Spec.Plugins) != 1 {
+		t.Errorf("unexpected conversion result %+v", out)
+	}
+}
@@ -0,0 +1,7 @@
+// +k8s:deepcopy-gen=package,register
+// +groupName=k8s.cni.cncf.io
+// +groupGoName=K8sCniCncfIo
+
+// Package v2alpha1 is the v2alpha1 version of the API. It describes the
+// network with a structured spec and converts to and from v1.
+package v2alpha1
@@ -0,0 +1,41 @@
+package v2alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +resourceName=network-attachment-definitions
+
+type NetworkAttachmentDefinition struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec NetworkAttachmentDefinitionSpec `json:"spec"`
+}
+
+// NetworkAttachmentDefinitionSpec describes the network as a CNI plugin chain.
+// The CNI network name is the name of the object.
+type NetworkAttachmentDefinitionSpec struct {
+	// CNIVersion is the CNI spec version of the plugin chain
+	CNIVersion string `json:"cniVersion,omitempty"`
+	// Plugins are the CNI plugin configurations, in invocation order
+	Plugins []runtime.RawExtension `json:"plugins,omitempty"`
+	// ResourceName is the device plugin resource backing the network
+	ResourceName string `json:"resourceName,omitempty"`
+	// Description is a human readable description of the network
+	Description string `json:"description,omitempty"`
+	// Tags are free-form labels used to group and search networks
+	Tags []string `json:"tags,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type NetworkAttachmentDefinitionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []NetworkAttachmentDefinition `json:"items"`
+}
@@ -28,6 +28,10 @@ type FakeK8sCniCncfIoV1 struct {
 	*testing.Fake
 }
 
+func (c *FakeK8sCniCncfIoV1) ClusterNetworkAttachmentDefinitions() v1.ClusterNetworkAttachmentDefinitionInterface {
+	return &FakeClusterNetworkAttachmentDefinitions{c}
+}
+
 func (c *FakeK8sCniCncfIoV1) NetworkAttachmentDefinitions(namespace string) v1.NetworkAttachmentDefinitionInterface {
 	return &FakeNetworkAttachmentDefinitions{c, namespace}
 }
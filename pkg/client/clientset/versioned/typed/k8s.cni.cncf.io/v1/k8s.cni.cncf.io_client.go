@@ -19,6 +19,8 @@ limitations under the License.
 package v1
 
 import (
+	"net/http"
+
 	v1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
 	"github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/client/clientset/versioned/scheme"
 	rest "k8s.io/client-go/rest"
@@ -26,6 +28,7 @@ import (
 
 type K8sCniCncfIoV1Interface interface {
 	RESTClient() rest.Interface
+	ClusterNetworkAttachmentDefinitionsGetter
 	NetworkAttachmentDefinitionsGetter
 }
 
@@ -34,17 +37,37 @@ type K8sCniCncfIoV1Client struct {
 	restClient rest.Interface
 }
 
+func (c *K8sCniCncfIoV1Client) ClusterNetworkAttachmentDefinitions() ClusterNetworkAttachmentDefinitionInterface {
+	return newClusterNetworkAttachmentDefinitions(c)
+}
+
 func (c *K8sCniCncfIoV1Client) NetworkAttachmentDefinitions(namespace string) NetworkAttachmentDefinitionInterface {
 	return newNetworkAttachmentDefinitions(c, namespace)
 }
 
 // NewForConfig creates a new K8sCniCncfIoV1Client for the given config.
+// NewForConfig is equivalent to NewForConfigAndClient(c, httpClient),
+// where httpClient was generated with rest.HTTPClientFor(c).
 func NewForConfig(c *rest.Config) (*K8sCniCncfIoV1Client, error) {
 	config := *c
 	if err := setConfigDefaults(&config); err != nil {
 		return nil, err
 	}
-	client, err := rest.RESTClientFor(&config)
+	httpClient, err := rest.HTTPClientFor(&config)
+	if err != nil {
+		return nil, err
+	}
+	return NewForConfigAndClient(&config, httpClient)
+}
+
+// NewForConfigAndClient creates a new K8sCniCncfIoV1Client for the given config and http client.
+// Note the http client provided takes precedence over the configured transport values.
+func NewForConfigAndClient(c *rest.Config, h *http.Client) (*K8sCniCncfIoV1Client, error) {
+	config := *c
+	if err := setConfigDefaults(&config); err != nil {
+		return nil, err
+	}
+	client, err := rest.RESTClientForConfigAndClient(&config, h)
 	if err != nil {
 		return nil, err
 	}
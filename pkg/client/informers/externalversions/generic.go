@@ -22,6 +22,7 @@ import (
 	"fmt"
 
 	v1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+	v2alpha1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v2alpha1"
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
 	cache "k8s.io/client-go/tools/cache"
 )
@@ -53,9 +54,15 @@ func (f *genericInformer) Lister() cache.GenericLister {
 func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource) (GenericInformer, error) {
 	switch resource {
 	// Group=k8s.cni.cncf.io, Version=v1
+	case v1.SchemeGroupVersion.WithResource("cluster-network-attachment-definitions"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.K8sCniCncfIo().V1().ClusterNetworkAttachmentDefinitions().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("network-attachment-definitions"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.K8sCniCncfIo().V1().NetworkAttachmentDefinitions().Informer()}, nil
 
+		// Group=k8s.cni.cncf.io, Version=v2alpha1
+	case v2alpha1.SchemeGroupVersion.WithResource("network-attachment-definitions"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.K8sCniCncfIo().V2alpha1().NetworkAttachmentDefinitions().Informer()}, nil
+
 	}
 
 	return nil, fmt.Errorf("no informer found for %v", resource)
@@ -24,6 +24,8 @@ import (
 
 // Interface provides access to all the informers in this group version.
 type Interface interface {
+	// ClusterNetworkAttachmentDefinitions returns a ClusterNetworkAttachmentDefinitionInformer.
+	ClusterNetworkAttachmentDefinitions() ClusterNetworkAttachmentDefinitionInformer
 	// NetworkAttachmentDefinitions returns a NetworkAttachmentDefinitionInformer.
 	NetworkAttachmentDefinitions() NetworkAttachmentDefinitionInformer
 }
@@ -39,6 +41,11 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 	return &version{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
 }
 
+// ClusterNetworkAttachmentDefinitions returns a ClusterNetworkAttachmentDefinitionInformer.
+func (v *version) ClusterNetworkAttachmentDefinitions() ClusterNetworkAttachmentDefinitionInformer {
+	return &clusterNetworkAttachmentDefinitionInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // NetworkAttachmentDefinitions returns a NetworkAttachmentDefinitionInformer.
 func (v *version) NetworkAttachmentDefinitions() NetworkAttachmentDefinitionInformer {
 	return &networkAttachmentDefinitionInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
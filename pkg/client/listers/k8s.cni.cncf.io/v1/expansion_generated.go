@@ -18,6 +18,10 @@ limitations under the License.
 
 package v1
 
+// ClusterNetworkAttachmentDefinitionListerExpansion allows custom methods to be added to
+// ClusterNetworkAttachmentDefinitionLister.
+type ClusterNetworkAttachmentDefinitionListerExpansion interface{}
+
 // NetworkAttachmentDefinitionListerExpansion allows custom methods to be added to
 // NetworkAttachmentDefinitionLister.
 type NetworkAttachmentDefinitionListerExpansion interface{}
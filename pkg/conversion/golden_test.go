@@ -0,0 +1,121 @@
+// Copyright (c) 2024 Kubernetes Network Plumbing Working Group
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversion
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	k8scnicncfio "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io"
+	"github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/client/clientset/versioned/scheme"
+)
+
+// update rewrites the golden files of the other versions from v1.json
+var update = flag.Bool("update", false, "update golden files from v1.json")
+
+// versions are the API versions with a golden file in every testdata case
+var versions = []string{"v1", "v2alpha1"}
+
+// TestGoldenRoundTrip converts the golden file of every version in each
+// testdata case to every other version and compares the result with the
+// golden file of that version
+func TestGoldenRoundTrip(t *testing.T) {
+	h := NewHandler(scheme.Scheme)
+
+	cases, err := os.ReadDir("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range cases {
+		dir := filepath.Join("testdata", c.Name())
+
+		if *update {
+			v1, err := os.ReadFile(filepath.Join(dir, "v1.json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, version := range versions[1:] {
+				converted, err := h.ConvertObject(v1, groupVersion(version))
+				if err != nil {
+					t.Fatalf("%s: failed to convert to %s: %v", c.Name(), version, err)
+				}
+				// keep the key order, which is significant in embedded plugins
+				var buf bytes.Buffer
+				if err := json.Indent(&buf, converted, "", "  "); err != nil {
+					t.Fatal(err)
+				}
+				buf.WriteString("\n")
+				if err := os.WriteFile(filepath.Join(dir, version+".json"), buf.Bytes(), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+
+		for _, from := range versions {
+			in, err := os.ReadFile(filepath.Join(dir, from+".json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, to := range versions {
+				expected, err := os.ReadFile(filepath.Join(dir, to+".json"))
+				if err != nil {
+					t.Fatal(err)
+				}
+				converted, err := h.ConvertObject(in, groupVersion(to))
+				if err != nil {
+					t.Errorf("%s: failed to convert %s to %s: %v", c.Name(), from, to, err)
+					continue
+				}
+				if !bytes.Equal(indent(t, converted), indent(t, expected)) {
+					t.Errorf("%s: converting %s to %s\nexpected %s\ngot      %s", c.Name(), from, to, indent(t, expected), indent(t, converted))
+				}
+			}
+		}
+	}
+}
+
+func groupVersion(version string) schema.GroupVersion {
+	return schema.GroupVersion{Group: k8scnicncfio.GroupName, Version: version}
+}
+
+// indent normalizes JSON so formatting and key order do not matter, also
+// in the v1 config, which is rendered compact when converted from another
+// version
+func indent(t *testing.T, data []byte) []byte {
+	var obj map[string]interface{}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		t.Fatalf("invalid JSON %s: %v", data, err)
+	}
+	if spec, ok := obj["spec"].(map[string]interface{}); ok {
+		if config, ok := spec["config"].(string); ok {
+			var configObj interface{}
+			if err := json.Unmarshal([]byte(config), &configObj); err == nil {
+				spec["config"] = configObj
+			}
+		}
+	}
+	out, err := json.MarshalIndent(obj, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return append(out, '\n')
+}
@@ -0,0 +1,130 @@
+// Copyright (c) 2024 Kubernetes Network Plumbing Working Group
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package conversion implements a CRD conversion webhook converting
+// NetworkAttachmentDefinitions between the served API versions.
+package conversion
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+)
+
+// Handler serves ConversionReview requests. Objects are converted with the
+// conversion functions registered in its scheme, such as the Convert_*
+// functions the API packages add through AddToScheme.
+type Handler struct {
+	scheme       *runtime.Scheme
+	deserializer runtime.Decoder
+}
+
+// NewHandler creates a Handler converting between the versions registered in
+// the scheme. The clientset scheme package provides a scheme with every
+// NetworkAttachmentDefinition version.
+func NewHandler(scheme *runtime.Scheme) *Handler {
+	return &Handler{
+		scheme:       scheme,
+		deserializer: serializer.NewCodecFactory(scheme).UniversalDeserializer(),
+	}
+}
+
+// ServeHTTP converts the objects of the ConversionReview in the request body
+// and writes the ConversionReview response
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST is supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to read request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	review := &apiextensionsv1.ConversionReview{}
+	if err := json.Unmarshal(body, review); err != nil {
+		http.Error(w, fmt.Sprintf("failed to decode ConversionReview: %v", err), http.StatusBadRequest)
+		return
+	}
+	if review.Request == nil {
+		http.Error(w, "ConversionReview has no request", http.StatusBadRequest)
+		return
+	}
+
+	review.Response = h.Convert(review.Request)
+	review.Request = nil
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(review); err != nil {
+		http.Error(w, fmt.Sprintf("failed to encode ConversionReview: %v", err), http.StatusInternalServerError)
+	}
+}
+
+// Convert converts the objects of the request to the desired API version.
+// Conversion failures are reported in the status of the response.
+func (h *Handler) Convert(req *apiextensionsv1.ConversionRequest) *apiextensionsv1.ConversionResponse {
+	resp := &apiextensionsv1.ConversionResponse{
+		UID:    req.UID,
+		Result: metav1.Status{Status: metav1.StatusSuccess},
+	}
+
+	gv, err := schema.ParseGroupVersion(req.DesiredAPIVersion)
+	if err != nil {
+		return failed(resp, fmt.Errorf("invalid desired API version %q: %v", req.DesiredAPIVersion, err))
+	}
+	if !h.scheme.IsVersionRegistered(gv) {
+		return failed(resp, fmt.Errorf("desired API version %q is not registered", req.DesiredAPIVersion))
+	}
+
+	for i, obj := range req.Objects {
+		converted, err := h.ConvertObject(obj.Raw, gv)
+		if err != nil {
+			return failed(resp, fmt.Errorf("failed to convert object %d: %v", i, err))
+		}
+		resp.ConvertedObjects = append(resp.ConvertedObjects, runtime.RawExtension{Raw: converted})
+	}
+	return resp
+}
+
+// ConvertObject converts a JSON encoded object to the group version and
+// returns it JSON encoded
+func (h *Handler) ConvertObject(raw []byte, gv schema.GroupVersion) ([]byte, error) {
+	in, _, err := h.deserializer.Decode(raw, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	out, err := h.scheme.ConvertToVersion(in, gv)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(out)
+}
+
+func failed(resp *apiextensionsv1.ConversionResponse, err error) *apiextensionsv1.ConversionResponse {
+	resp.ConvertedObjects = nil
+	resp.Result = metav1.Status{
+		Status:  metav1.StatusFailure,
+		Message: err.Error(),
+	}
+	return resp
+}
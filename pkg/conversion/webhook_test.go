@@ -0,0 +1,115 @@
+// Copyright (c) 2024 Kubernetes Network Plumbing Working Group
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversion
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	v2alpha1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v2alpha1"
+	"github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/client/clientset/versioned/scheme"
+)
+
+func review(t *testing.T, server *httptest.Server, req *apiextensionsv1.ConversionRequest) *apiextensionsv1.ConversionResponse {
+	body, err := json.Marshal(&apiextensionsv1.ConversionReview{
+		TypeMeta: metav1.TypeMeta{APIVersion: "apiextensions.k8s.io/v1", Kind: "ConversionReview"},
+		Request:  req,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.Post(server.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status %d", resp.StatusCode)
+	}
+
+	out := &apiextensionsv1.ConversionReview{}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Response == nil {
+		t.Fatal("no response in ConversionReview")
+	}
+	return out.Response
+}
+
+func TestHandlerConvertsToV2alpha1(t *testing.T) {
+	server := httptest.NewServer(NewHandler(scheme.Scheme))
+	defer server.Close()
+
+	resp := review(t, server, &apiextensionsv1.ConversionRequest{
+		UID:               "1234",
+		DesiredAPIVersion: "k8s.cni.cncf.io/v2alpha1",
+		Objects: []runtime.RawExtension{{Raw: []byte(`{
+			"apiVersion": "k8s.cni.cncf.io/v1",
+			"kind": "NetworkAttachmentDefinition",
+			"metadata": {"name": "net1", "namespace": "default"},
+			"spec": {"config": "{\"cniVersion\":\"1.0.0\",\"name\":\"net1\",\"plugins\":[{\"type\":\"bridge\"}]}"}
+		}`)}},
+	})
+	if resp.UID != "1234" || resp.Result.Status != metav1.StatusSuccess {
+		t.Fatalf("unexpected response %+v", resp)
+	}
+	if len(resp.ConvertedObjects) != 1 {
+		t.Fatalf("expected 1 converted object, got %d", len(resp.ConvertedObjects))
+	}
+
+	nad := &v2alpha1.NetworkAttachmentDefinition{}
+	if err := json.Unmarshal(resp.ConvertedObjects[0].Raw, nad); err != nil {
+		t.Fatal(err)
+	}
+	if nad.APIVersion != "k8s.cni.cncf.io/v2alpha1" || nad.Kind != "NetworkAttachmentDefinition" {
+		t.Errorf("unexpected type %s %s", nad.APIVersion, nad.Kind)
+	}
+	if nad.Spec.CNIVersion != "1.0.0" || len(nad.Spec.Plugins) != 1 || string(nad.Spec.Plugins[0].Raw) != `{"type":"bridge"}` {
+		t.Errorf("unexpected spec %+v", nad.Spec)
+	}
+}
+
+func TestHandlerReportsFailures(t *testing.T) {
+	server := httptest.NewServer(NewHandler(scheme.Scheme))
+	defer server.Close()
+
+	resp := review(t, server, &apiextensionsv1.ConversionRequest{
+		UID:               "5678",
+		DesiredAPIVersion: "k8s.cni.cncf.io/v2alpha1",
+		Objects:           []runtime.RawExtension{{Raw: []byte(`{"apiVersion": "example.com/v1", "kind": "Unknown"}`)}},
+	})
+	if resp.UID != "5678" || resp.Result.Status != metav1.StatusFailure {
+		t.Errorf("expected failure, got %+v", resp)
+	}
+	if len(resp.ConvertedObjects) != 0 {
+		t.Errorf("expected no converted objects, got %d", len(resp.ConvertedObjects))
+	}
+
+	resp = review(t, server, &apiextensionsv1.ConversionRequest{
+		UID:               "9012",
+		DesiredAPIVersion: "k8s.cni.cncf.io/v3",
+	})
+	if resp.Result.Status != metav1.StatusFailure {
+		t.Errorf("expected failure for unregistered version, got %+v", resp)
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright (c) 2024 Kubernetes Network Plumbing Working Group
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipam
+
+import (
+	"fmt"
+	"net/netip"
+	"sort"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	v1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+)
+
+// NetworkRange is an IPAM range along with the network it belongs to
+type NetworkRange struct {
+	Namespace string
+	Name      string
+	Range     Range
+	Exclude   []netip.Prefix
+}
+
+// RangeConflict reports two NetworkAttachmentDefinitions whose IPAM ranges
+// may hand out the same addresses
+type RangeConflict struct {
+	A NetworkRange
+	B NetworkRange
+	// OverlapStart and OverlapEnd bound the addresses both ranges can allocate
+	OverlapStart netip.Addr
+	OverlapEnd   netip.Addr
+}
+
+func (c RangeConflict) String() string {
+	return fmt.Sprintf("%s/%s range %s overlaps %s/%s range %s (%s-%s)",
+		c.A.Namespace, c.A.Name, c.A.Range, c.B.Namespace, c.B.Name, c.B.Range,
+		c.OverlapStart, c.OverlapEnd)
+}
+
+// FindRangeConflicts reports every pair of NetworkAttachmentDefinitions with
+// overlapping IPAM ranges. Overlaps entirely covered by excludes of either
+// network are not reported. NetworkAttachmentDefinitions whose config cannot
+// be parsed are skipped and reported in the returned error.
+func FindRangeConflicts(nads []v1.NetworkAttachmentDefinition) ([]RangeConflict, error) {
+	var ranges []NetworkRange
+	var errs []error
+
+	for i := range nads {
+		configs, err := GetIPAMConfigsFromNAD(&nads[i])
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, conf := range configs {
+			for _, rng := range conf.Ranges {
+				ranges = append(ranges, NetworkRange{
+					Namespace: nads[i].Namespace,
+					Name:      nads[i].Name,
+					Range:     rng,
+					Exclude:   conf.Exclude,
+				})
+			}
+		}
+	}
+
+	var conflicts []RangeConflict
+	for i := 0; i < len(ranges); i++ {
+		for j := i + 1; j < len(ranges); j++ {
+			a, b := ranges[i], ranges[j]
+			if a.Namespace == b.Namespace && a.Name == b.Name {
+				continue
+			}
+			start, end, ok := overlap(a.Range, b.Range)
+			if !ok {
+				continue
+			}
+			if covered(start, end, append(append([]netip.Prefix{}, a.Exclude...), b.Exclude...)) {
+				continue
+			}
+			conflicts = append(conflicts, RangeConflict{
+				A:            a,
+				B:            b,
+				OverlapStart: start,
+				OverlapEnd:   end,
+			})
+		}
+	}
+
+	return conflicts, utilerrors.NewAggregate(errs)
+}
+
+// overlap returns the intersection of two ranges, if any
+func overlap(a, b Range) (netip.Addr, netip.Addr, bool) {
+	if a.Subnet.Addr().Is4() != b.Subnet.Addr().Is4() {
+		return netip.Addr{}, netip.Addr{}, false
+	}
+
+	start := a.First()
+	if b.First().Compare(start) > 0 {
+		start = b.First()
+	}
+	end := a.Last()
+	if b.Last().Less(end) {
+		end = b.Last()
+	}
+	if end.Less(start) {
+		return netip.Addr{}, netip.Addr{}, false
+	}
+	return start, end, true
+}
+
+// covered reports whether every address in [start, end] is in one of the prefixes
+func covered(start, end netip.Addr, prefixes []netip.Prefix) bool {
+	sort.Slice(prefixes, func(i, j int) bool {
+		return prefixes[i].Addr().Less(prefixes[j].Addr())
+	})
+
+	next := start
+	for _, prefix := range prefixes {
+		if prefix.Addr().Is4() != start.Is4() {
+			continue
+		}
+		first, last := prefix.Masked().Addr(), lastAddr(prefix)
+		if next.Less(first) {
+			return false
+		}
+		if last.Less(next) {
+			continue
+		}
+		if !last.Less(end) {
+			return true
+		}
+		next = last.Next()
+	}
+	return false
+}
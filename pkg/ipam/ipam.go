@@ -0,0 +1,339 @@
+// Copyright (c) 2024 Kubernetes Network Plumbing Working Group
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipam
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/netip"
+	"strings"
+
+	cnitypes "github.com/containernetworking/cni/pkg/types"
+
+	v1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+)
+
+const (
+	TypeHostLocal   = "host-local"
+	TypeStatic      = "static"
+	TypeWhereabouts = "whereabouts"
+	TypeDHCP        = "dhcp"
+)
+
+// Range is a contiguous block of addresses an IPAM plugin may hand out
+type Range struct {
+	Subnet     netip.Prefix
+	RangeStart netip.Addr
+	RangeEnd   netip.Addr
+	Gateway    netip.Addr
+}
+
+// First returns the first address of the range, falling back to the
+// subnet's network address when no explicit start is configured
+func (r Range) First() netip.Addr {
+	if r.RangeStart.IsValid() {
+		return r.RangeStart
+	}
+	return r.Subnet.Masked().Addr()
+}
+
+// Last returns the last address of the range, falling back to the
+// subnet's broadcast address when no explicit end is configured
+func (r Range) Last() netip.Addr {
+	if r.RangeEnd.IsValid() {
+		return r.RangeEnd
+	}
+	return lastAddr(r.Subnet)
+}
+
+func (r Range) String() string {
+	if !r.RangeStart.IsValid() && !r.RangeEnd.IsValid() {
+		return r.Subnet.String()
+	}
+	return fmt.Sprintf("%s-%s/%d", r.First(), r.Last(), r.Subnet.Bits())
+}
+
+// Config is the IPAM configuration found in one plugin of a CNI config
+type Config struct {
+	// Type is the IPAM plugin type (e.g. host-local, static, whereabouts, dhcp)
+	Type string
+	// PluginType is the type of the CNI plugin that carries this IPAM section
+	PluginType string
+	Ranges     []Range
+	Exclude    []netip.Prefix
+	Routes     []*cnitypes.Route
+}
+
+// rawRange covers the range fields used by host-local and whereabouts
+type rawRange struct {
+	Subnet     string   `json:"subnet,omitempty"`
+	RangeStart string   `json:"rangeStart,omitempty"`
+	RangeEnd   string   `json:"rangeEnd,omitempty"`
+	Gateway    string   `json:"gateway,omitempty"`
+	Range      string   `json:"range,omitempty"`
+	WbStart    string   `json:"range_start,omitempty"`
+	WbEnd      string   `json:"range_end,omitempty"`
+	Exclude    []string `json:"exclude,omitempty"`
+}
+
+type rawAddress struct {
+	Address string `json:"address"`
+	Gateway string `json:"gateway,omitempty"`
+}
+
+type rawIPAM struct {
+	Type string `json:"type"`
+	rawRange
+	Ranges    [][]rawRange      `json:"ranges,omitempty"`
+	IPRanges  []rawRange        `json:"ipRanges,omitempty"`
+	Addresses []rawAddress      `json:"addresses,omitempty"`
+	Routes    []*cnitypes.Route `json:"routes,omitempty"`
+}
+
+type rawPlugin struct {
+	Type string   `json:"type"`
+	IPAM *rawIPAM `json:"ipam,omitempty"`
+}
+
+type rawConfig struct {
+	rawPlugin
+	Plugins []rawPlugin `json:"plugins,omitempty"`
+}
+
+// GetIPAMConfigs extracts the IPAM configuration of every plugin in a CNI
+// config or config list. Plugins without an "ipam" section are skipped.
+func GetIPAMConfigs(config []byte) ([]*Config, error) {
+	var raw rawConfig
+	if err := json.Unmarshal(config, &raw); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal CNI config: %v", err)
+	}
+
+	plugins := raw.Plugins
+	if len(plugins) == 0 {
+		plugins = []rawPlugin{raw.rawPlugin}
+	}
+
+	var configs []*Config
+	for _, plugin := range plugins {
+		if plugin.IPAM == nil {
+			continue
+		}
+		conf, err := parseIPAM(plugin.IPAM)
+		if err != nil {
+			return nil, fmt.Errorf("plugin %q: %v", plugin.Type, err)
+		}
+		conf.PluginType = plugin.Type
+		configs = append(configs, conf)
+	}
+	return configs, nil
+}
+
+// GetIPAMConfigsFromNAD extracts the IPAM configuration from the
+// NetworkAttachmentDefinition's Spec.Config
+func GetIPAMConfigsFromNAD(nad *v1.NetworkAttachmentDefinition) ([]*Config, error) {
+	if nad == nil {
+		return nil, fmt.Errorf("no network attachment definition set")
+	}
+	if nad.Spec.Config == "" {
+		return nil, nil
+	}
+	configs, err := GetIPAMConfigs([]byte(nad.Spec.Config))
+	if err != nil {
+		return nil, fmt.Errorf("%s/%s: %v", nad.Namespace, nad.Name, err)
+	}
+	return configs, nil
+}
+
+func parseIPAM(raw *rawIPAM) (*Config, error) {
+	conf := &Config{
+		Type:   raw.Type,
+		Routes: raw.Routes,
+	}
+
+	switch raw.Type {
+	case TypeHostLocal:
+		rangeSets := raw.Ranges
+		if raw.Subnet != "" {
+			rangeSets = append([][]rawRange{{raw.rawRange}}, rangeSets...)
+		}
+		for _, set := range rangeSets {
+			for _, r := range set {
+				rng, err := parseHostLocalRange(r)
+				if err != nil {
+					return nil, err
+				}
+				conf.Ranges = append(conf.Ranges, rng)
+			}
+		}
+	case TypeWhereabouts:
+		ranges := raw.IPRanges
+		if raw.Range != "" {
+			ranges = append([]rawRange{raw.rawRange}, ranges...)
+		}
+		for _, r := range ranges {
+			rng, err := parseWhereaboutsRange(r)
+			if err != nil {
+				return nil, err
+			}
+			if rng.Gateway, err = parseOptionalAddr(r.Gateway); err != nil {
+				return nil, fmt.Errorf("invalid gateway: %v", err)
+			}
+			conf.Ranges = append(conf.Ranges, rng)
+			excludes, err := parsePrefixes(r.Exclude)
+			if err != nil {
+				return nil, fmt.Errorf("invalid exclude: %v", err)
+			}
+			conf.Exclude = append(conf.Exclude, excludes...)
+		}
+	case TypeStatic:
+		for _, addr := range raw.Addresses {
+			prefix, err := netip.ParsePrefix(addr.Address)
+			if err != nil {
+				return nil, fmt.Errorf("invalid static address: %v", err)
+			}
+			rng := Range{
+				Subnet:     prefix.Masked(),
+				RangeStart: prefix.Addr(),
+				RangeEnd:   prefix.Addr(),
+			}
+			if rng.Gateway, err = parseOptionalAddr(addr.Gateway); err != nil {
+				return nil, fmt.Errorf("invalid gateway: %v", err)
+			}
+			conf.Ranges = append(conf.Ranges, rng)
+		}
+	case TypeDHCP:
+		// Addresses are handed out by an external DHCP server
+	}
+
+	return conf, nil
+}
+
+func parseHostLocalRange(r rawRange) (Range, error) {
+	var rng Range
+	var err error
+
+	if rng.Subnet, err = netip.ParsePrefix(r.Subnet); err != nil {
+		return rng, fmt.Errorf("invalid subnet: %v", err)
+	}
+	rng.Subnet = rng.Subnet.Masked()
+	if rng.RangeStart, err = parseOptionalAddr(r.RangeStart); err != nil {
+		return rng, fmt.Errorf("invalid rangeStart: %v", err)
+	}
+	if rng.RangeEnd, err = parseOptionalAddr(r.RangeEnd); err != nil {
+		return rng, fmt.Errorf("invalid rangeEnd: %v", err)
+	}
+	if rng.Gateway, err = parseOptionalAddr(r.Gateway); err != nil {
+		return rng, fmt.Errorf("invalid gateway: %v", err)
+	}
+	return rng, validateRange(rng)
+}
+
+// parseWhereaboutsRange accepts both the CIDR form ("10.0.0.0/24") and
+// the inline range form ("10.0.0.10-10.0.0.20/24") of a whereabouts range
+func parseWhereaboutsRange(r rawRange) (Range, error) {
+	var rng Range
+	var err error
+
+	cidr := r.Range
+	if dash := strings.Index(cidr, "-"); dash >= 0 {
+		slash := strings.Index(cidr, "/")
+		if slash < dash {
+			return rng, fmt.Errorf("invalid range %q", r.Range)
+		}
+		if rng.RangeStart, err = netip.ParseAddr(cidr[:dash]); err != nil {
+			return rng, fmt.Errorf("invalid range: %v", err)
+		}
+		if rng.RangeEnd, err = netip.ParseAddr(cidr[dash+1 : slash]); err != nil {
+			return rng, fmt.Errorf("invalid range: %v", err)
+		}
+		cidr = cidr[:dash] + cidr[slash:]
+	}
+
+	if rng.Subnet, err = netip.ParsePrefix(cidr); err != nil {
+		return rng, fmt.Errorf("invalid range: %v", err)
+	}
+	rng.Subnet = rng.Subnet.Masked()
+	if r.WbStart != "" {
+		if rng.RangeStart, err = netip.ParseAddr(r.WbStart); err != nil {
+			return rng, fmt.Errorf("invalid range_start: %v", err)
+		}
+	}
+	if r.WbEnd != "" {
+		if rng.RangeEnd, err = netip.ParseAddr(r.WbEnd); err != nil {
+			return rng, fmt.Errorf("invalid range_end: %v", err)
+		}
+	}
+	return rng, validateRange(rng)
+}
+
+func validateRange(rng Range) error {
+	for _, addr := range []netip.Addr{rng.RangeStart, rng.RangeEnd, rng.Gateway} {
+		if addr.IsValid() && !rng.Subnet.Contains(addr) {
+			return fmt.Errorf("%s is not in subnet %s", addr, rng.Subnet)
+		}
+	}
+	if rng.Last().Less(rng.First()) {
+		return fmt.Errorf("range end %s is before range start %s", rng.Last(), rng.First())
+	}
+	return nil
+}
+
+func parseOptionalAddr(s string) (netip.Addr, error) {
+	if s == "" {
+		return netip.Addr{}, nil
+	}
+	return netip.ParseAddr(s)
+}
+
+// parsePrefixes parses whereabouts excludes, which may be CIDRs or single addresses
+func parsePrefixes(items []string) ([]netip.Prefix, error) {
+	var prefixes []netip.Prefix
+	for _, item := range items {
+		if !strings.Contains(item, "/") {
+			addr, err := netip.ParseAddr(item)
+			if err != nil {
+				return nil, err
+			}
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(item)
+		if err != nil {
+			return nil, err
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+// lastAddr returns the highest address in the prefix
+func lastAddr(prefix netip.Prefix) netip.Addr {
+	addr := prefix.Masked().Addr()
+	bytes := addr.AsSlice()
+	for bit := prefix.Bits(); bit < addr.BitLen(); bit++ {
+		bytes[bit/8] |= 0x80 >> (bit % 8)
+	}
+	last, _ := netip.AddrFromSlice(bytes)
+	return last
+}
+
+// ToIPNet converts the prefix into the net.IPNet form used by CNI types
+func ToIPNet(prefix netip.Prefix) *net.IPNet {
+	return &net.IPNet{
+		IP:   net.IP(prefix.Masked().Addr().AsSlice()),
+		Mask: net.CIDRMask(prefix.Bits(), prefix.Addr().BitLen()),
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright (c) 2024 Kubernetes Network Plumbing Working Group
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipam
+
+import (
+	"net/netip"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func newNAD(namespace, name, config string) v1.NetworkAttachmentDefinition {
+	return v1.NetworkAttachmentDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: v1.NetworkAttachmentDefinitionSpec{
+			Config: config,
+		},
+	}
+}
+
+var _ = Describe("IPAM config extraction", func() {
+	It("extracts host-local ranges from a single config", func() {
+		configs, err := GetIPAMConfigs([]byte(`{
+			"cniVersion": "0.3.1",
+			"type": "macvlan",
+			"ipam": {
+				"type": "host-local",
+				"subnet": "10.1.0.0/24",
+				"rangeStart": "10.1.0.10",
+				"rangeEnd": "10.1.0.20",
+				"gateway": "10.1.0.1",
+				"routes": [{"dst": "0.0.0.0/0"}],
+				"ranges": [[{"subnet": "2001:db8::/64"}]]
+			}
+		}`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(configs).To(HaveLen(1))
+		Expect(configs[0].Type).To(Equal(TypeHostLocal))
+		Expect(configs[0].PluginType).To(Equal("macvlan"))
+		Expect(configs[0].Ranges).To(HaveLen(2))
+		Expect(configs[0].Ranges[0].String()).To(Equal("10.1.0.10-10.1.0.20/24"))
+		Expect(configs[0].Ranges[0].Gateway).To(Equal(netip.MustParseAddr("10.1.0.1")))
+		Expect(configs[0].Ranges[1].String()).To(Equal("2001:db8::/64"))
+		Expect(configs[0].Routes).To(HaveLen(1))
+	})
+
+	It("extracts whereabouts ranges and excludes from a conflist", func() {
+		configs, err := GetIPAMConfigs([]byte(`{
+			"cniVersion": "0.3.1",
+			"name": "wb",
+			"plugins": [{
+				"type": "bridge",
+				"ipam": {
+					"type": "whereabouts",
+					"range": "192.168.2.225-192.168.2.230/28",
+					"exclude": ["192.168.2.229/30"],
+					"ipRanges": [{"range": "fd00::/120", "range_start": "fd00::10"}]
+				}
+			}, {
+				"type": "tuning"
+			}]
+		}`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(configs).To(HaveLen(1))
+		Expect(configs[0].Ranges).To(HaveLen(2))
+		Expect(configs[0].Ranges[0].First()).To(Equal(netip.MustParseAddr("192.168.2.225")))
+		Expect(configs[0].Ranges[0].Last()).To(Equal(netip.MustParseAddr("192.168.2.230")))
+		Expect(configs[0].Ranges[1].First()).To(Equal(netip.MustParseAddr("fd00::10")))
+		Expect(configs[0].Exclude).To(ConsistOf(netip.MustParsePrefix("192.168.2.228/30")))
+	})
+
+	It("extracts static addresses and tolerates dhcp", func() {
+		configs, err := GetIPAMConfigs([]byte(`{
+			"name": "static",
+			"plugins": [
+				{"type": "macvlan", "ipam": {"type": "static", "addresses": [{"address": "10.10.0.1/24", "gateway": "10.10.0.254"}]}},
+				{"type": "ipvlan", "ipam": {"type": "dhcp"}}
+			]
+		}`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(configs).To(HaveLen(2))
+		Expect(configs[0].Ranges[0].First()).To(Equal(netip.MustParseAddr("10.10.0.1")))
+		Expect(configs[0].Ranges[0].Last()).To(Equal(netip.MustParseAddr("10.10.0.1")))
+		Expect(configs[1].Type).To(Equal(TypeDHCP))
+		Expect(configs[1].Ranges).To(BeEmpty())
+	})
+
+	It("rejects a range start outside of the subnet", func() {
+		_, err := GetIPAMConfigs([]byte(`{"type": "macvlan", "ipam": {"type": "host-local", "subnet": "10.1.0.0/24", "rangeStart": "10.2.0.1"}}`))
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("IPAM range conflicts", func() {
+	It("reports overlapping ranges across NADs", func() {
+		nads := []v1.NetworkAttachmentDefinition{
+			newNAD("ns1", "net-a", `{"type": "macvlan", "ipam": {"type": "whereabouts", "range": "10.1.0.0/24"}}`),
+			newNAD("ns2", "net-b", `{"type": "macvlan", "ipam": {"type": "host-local", "subnet": "10.1.0.0/16", "rangeStart": "10.1.0.200", "rangeEnd": "10.1.1.10"}}`),
+			newNAD("ns2", "net-c", `{"type": "macvlan", "ipam": {"type": "host-local", "subnet": "10.2.0.0/16"}}`),
+		}
+		conflicts, err := FindRangeConflicts(nads)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(conflicts).To(HaveLen(1))
+		Expect(conflicts[0].A.Name).To(Equal("net-a"))
+		Expect(conflicts[0].B.Name).To(Equal("net-b"))
+		Expect(conflicts[0].OverlapStart).To(Equal(netip.MustParseAddr("10.1.0.200")))
+		Expect(conflicts[0].OverlapEnd).To(Equal(netip.MustParseAddr("10.1.0.255")))
+	})
+
+	It("ignores overlaps that are excluded", func() {
+		nads := []v1.NetworkAttachmentDefinition{
+			newNAD("ns1", "net-a", `{"type": "macvlan", "ipam": {"type": "whereabouts", "range": "10.1.0.0/24", "exclude": ["10.1.0.128/25"]}}`),
+			newNAD("ns1", "net-b", `{"type": "macvlan", "ipam": {"type": "host-local", "subnet": "10.1.0.0/24", "rangeStart": "10.1.0.128"}}`),
+		}
+		conflicts, err := FindRangeConflicts(nads)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(conflicts).To(BeEmpty())
+	})
+
+	It("reports unparsable NADs without dropping other conflicts", func() {
+		nads := []v1.NetworkAttachmentDefinition{
+			newNAD("ns1", "net-a", `{"type": "macvlan", "ipam": {"type": "static", "addresses": [{"address": "10.1.0.5/24"}]}}`),
+			newNAD("ns1", "net-b", `{"type": "macvlan", "ipam": {"type": "host-local", "subnet": "10.1.0.0/24"}}`),
+			newNAD("ns1", "broken", `{invalid`),
+		}
+		conflicts, err := FindRangeConflicts(nads)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("ns1/broken"))
+		Expect(conflicts).To(HaveLen(1))
+	})
+})
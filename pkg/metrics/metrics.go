@@ -0,0 +1,178 @@
+// Copyright (c) 2024 Kubernetes Network Plumbing Working Group
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package metrics provides optional Prometheus metrics for the utils
+// operations and the NetworkAttachmentDefinitions of an informer. Nothing is
+// registered unless a Registerer is given.
+package metrics
+
+import (
+	"sort"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/labels"
+
+	listers "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/client/listers/k8s.cni.cncf.io/v1"
+	"github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/utils"
+)
+
+const namespace = "network_attachment_definition_client"
+
+// Recorder records the utils operations in Prometheus metrics. It
+// implements utils.MetricsRecorder.
+type Recorder struct {
+	statusUpdateDuration *prometheus.HistogramVec
+	statusUpdateRetries  prometheus.Counter
+	configFileDuration   *prometheus.HistogramVec
+	annotationErrors     *prometheus.CounterVec
+	deviceInfoOperations *prometheus.CounterVec
+}
+
+var _ utils.MetricsRecorder = &Recorder{}
+
+// NewRecorder creates the metrics of the utils operations and registers them
+// with reg. Pass the recorder to utils.SetMetricsRecorder to record them
+// process-wide, or to utils.WithMetricsRecorder to record the operations of
+// the functions given the context.
+func NewRecorder(reg prometheus.Registerer) (*Recorder, error) {
+	r := &Recorder{
+		statusUpdateDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "network_status_update_duration_seconds",
+			Help:      "Duration of the pod network status updates, including retries.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"result"}),
+		statusUpdateRetries: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "network_status_update_retries_total",
+			Help:      "Number of pod network status updates retried after a conflict.",
+		}),
+		configFileDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "cni_config_file_load_duration_seconds",
+			Help:      "Duration of the CNI configuration file lookups in the configuration directory.",
+			Buckets:   prometheus.ExponentialBuckets(0.0005, 2, 12),
+		}, []string{"result"}),
+		annotationErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "network_annotation_parse_errors_total",
+			Help:      "Number of network selection annotations that failed to parse, by reason.",
+		}, []string{"reason"}),
+		deviceInfoOperations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "device_info_operations_total",
+			Help:      "Number of device info file operations, by operation and result.",
+		}, []string{"operation", "result"}),
+	}
+
+	for _, c := range []prometheus.Collector{r.statusUpdateDuration, r.statusUpdateRetries, r.configFileDuration, r.annotationErrors, r.deviceInfoOperations} {
+		if err := reg.Register(c); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+func result(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "success"
+}
+
+// ObserveNetworkStatusUpdate implements utils.MetricsRecorder
+func (r *Recorder) ObserveNetworkStatusUpdate(duration time.Duration, retries int, err error) {
+	r.statusUpdateDuration.WithLabelValues(result(err)).Observe(duration.Seconds())
+	r.statusUpdateRetries.Add(float64(retries))
+}
+
+// ObserveConfigFileLoad implements utils.MetricsRecorder
+func (r *Recorder) ObserveConfigFileLoad(duration time.Duration, err error) {
+	r.configFileDuration.WithLabelValues(result(err)).Observe(duration.Seconds())
+}
+
+// IncAnnotationParseError implements utils.MetricsRecorder
+func (r *Recorder) IncAnnotationParseError(reason string) {
+	r.annotationErrors.WithLabelValues(reason).Inc()
+}
+
+// IncDeviceInfoOperation implements utils.MetricsRecorder
+func (r *Recorder) IncDeviceInfoOperation(operation string, err error) {
+	r.deviceInfoOperations.WithLabelValues(operation, result(err)).Inc()
+}
+
+// networkCollector computes gauges of the NetworkAttachmentDefinitions of a
+// lister when scraped
+type networkCollector struct {
+	lister      listers.NetworkAttachmentDefinitionLister
+	byNamespace *prometheus.Desc
+	byPlugin    *prometheus.Desc
+}
+
+// NewNetworkCollector returns a collector of the number of
+// NetworkAttachmentDefinitions per namespace and per plugin type, read from
+// the lister of an informer when scraped. Register it with a Registerer.
+func NewNetworkCollector(lister listers.NetworkAttachmentDefinitionLister) prometheus.Collector {
+	return &networkCollector{
+		lister: lister,
+		byNamespace: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "networks"),
+			"Number of NetworkAttachmentDefinitions per namespace.",
+			[]string{"namespace"}, nil),
+		byPlugin: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "networks_by_plugin"),
+			"Number of NetworkAttachmentDefinitions using a CNI plugin type.",
+			[]string{"type"}, nil),
+	}
+}
+
+// Describe implements prometheus.Collector
+func (c *networkCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.byNamespace
+	ch <- c.byPlugin
+}
+
+// Collect implements prometheus.Collector
+func (c *networkCollector) Collect(ch chan<- prometheus.Metric) {
+	nads, err := c.lister.List(labels.Everything())
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(c.byNamespace, err)
+		return
+	}
+
+	namespaces := map[string]int{}
+	plugins := map[string]int{}
+	for _, nad := range nads {
+		namespaces[nad.Namespace]++
+		for _, pluginType := range utils.PluginTypes(nad.Spec.Config) {
+			plugins[pluginType]++
+		}
+	}
+	for _, ns := range sortedKeys(namespaces) {
+		ch <- prometheus.MustNewConstMetric(c.byNamespace, prometheus.GaugeValue, float64(namespaces[ns]), ns)
+	}
+	for _, pluginType := range sortedKeys(plugins) {
+		ch <- prometheus.MustNewConstMetric(c.byPlugin, prometheus.GaugeValue, float64(plugins[pluginType]), pluginType)
+	}
+}
+
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,167 @@
+// Copyright (c) 2024 Kubernetes Network Plumbing Working Group
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+
+	v1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+	listers "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/client/listers/k8s.cni.cncf.io/v1"
+	"github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/utils"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Metrics", func() {
+	var registry *prometheus.Registry
+	var recorder *Recorder
+
+	BeforeEach(func() {
+		var err error
+		registry = prometheus.NewRegistry()
+		recorder, err = NewRecorder(registry)
+		Expect(err).NotTo(HaveOccurred())
+		utils.SetMetricsRecorder(recorder)
+	})
+
+	AfterEach(func() {
+		utils.SetMetricsRecorder(nil)
+	})
+
+	It("fails to register twice", func() {
+		_, err := NewRecorder(registry)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("records the utils operations", func() {
+		_, err := utils.ParseNetworkAnnotation(`[{"name": "net1"`, "default")
+		Expect(err).To(HaveOccurred())
+		_, err = utils.ParseNetworkAnnotation("a/b/c", "default")
+		Expect(err).To(HaveOccurred())
+		_, err = utils.ParseNetworkAnnotation("net1,net2", "default")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(testutil.ToFloat64(recorder.annotationErrors.WithLabelValues("json"))).To(Equal(1.0))
+		Expect(testutil.ToFloat64(recorder.annotationErrors.WithLabelValues("format"))).To(Equal(1.0))
+
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod1", Namespace: "default"}}
+		client := fake.NewSimpleClientset(pod)
+		Expect(utils.SetNetworkStatus(client, pod, []v1.NetworkStatus{{Name: "default/net1"}})).To(Succeed())
+		Expect(testutil.CollectAndCount(recorder.statusUpdateDuration)).To(Equal(1))
+		Expect(testutil.ToFloat64(recorder.statusUpdateRetries)).To(Equal(0.0))
+
+		tmpDir, err := os.MkdirTemp("", "multus-tmp")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(tmpDir)
+		Expect(os.WriteFile(filepath.Join(tmpDir, "10-net1.conf"), []byte(`{"cniVersion": "1.0.0", "name": "net1", "type": "bridge"}`), 0644)).To(Succeed())
+		_, err = utils.GetCNIConfigFromFile("net1", tmpDir)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = utils.GetCNIConfigFromFile("missing", tmpDir)
+		Expect(err).To(HaveOccurred())
+		Expect(testutil.CollectAndCount(recorder.configFileDuration)).To(Equal(2))
+
+		devInfoPath := filepath.Join(tmpDir, "devinfo.json")
+		Expect(utils.SaveDeviceInfoForCNI(devInfoPath, &v1.DeviceInfo{Type: v1.DeviceInfoTypePCI, Version: "1.1.0"})).To(Succeed())
+		_, err = utils.LoadDeviceInfoFromCNI(filepath.Join(tmpDir, "missing.json"))
+		Expect(err).To(HaveOccurred())
+		Expect(utils.CleanDeviceInfoForCNI(devInfoPath)).To(Succeed())
+		Expect(testutil.ToFloat64(recorder.deviceInfoOperations.WithLabelValues("save", "success"))).To(Equal(1.0))
+		Expect(testutil.ToFloat64(recorder.deviceInfoOperations.WithLabelValues("load", "error"))).To(Equal(1.0))
+		Expect(testutil.ToFloat64(recorder.deviceInfoOperations.WithLabelValues("clean", "success"))).To(Equal(1.0))
+	})
+
+	It("stops recording when the recorder is unset", func() {
+		utils.SetMetricsRecorder(nil)
+		_, err := utils.ParseNetworkAnnotation("", "default")
+		Expect(err).To(HaveOccurred())
+		Expect(testutil.ToFloat64(recorder.annotationErrors.WithLabelValues("empty"))).To(Equal(0.0))
+	})
+
+	It("records to the recorder of the context", func() {
+		ctxRecorder, err := NewRecorder(prometheus.NewRegistry())
+		Expect(err).NotTo(HaveOccurred())
+		ctx := utils.WithMetricsRecorder(context.Background(), ctxRecorder)
+
+		tmpDir, err := os.MkdirTemp("", "multus-tmp")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(tmpDir)
+		Expect(os.WriteFile(filepath.Join(tmpDir, "10-net1.conf"), []byte(`{"cniVersion": "1.0.0", "name": "net1", "type": "bridge"}`), 0644)).To(Succeed())
+
+		indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+		Expect(indexer.Add(&v1.NetworkAttachmentDefinition{ObjectMeta: metav1.ObjectMeta{Name: "net1", Namespace: "default"}})).To(Succeed())
+		getter := utils.NewListerNetworkGetter(listers.NewNetworkAttachmentDefinitionLister(indexer), nil)
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name: "pod1", Namespace: "default",
+			Annotations: map[string]string{v1.NetworkAttachmentAnnot: "net1"},
+		}}
+		_, err = utils.ResolveNetworkSelections(ctx, pod, getter, tmpDir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(testutil.CollectAndCount(ctxRecorder.configFileDuration)).To(Equal(1))
+		Expect(testutil.CollectAndCount(recorder.configFileDuration)).To(Equal(0))
+	})
+
+	It("records annotation errors to the recorder of the context", func() {
+		ctxRecorder, err := NewRecorder(prometheus.NewRegistry())
+		Expect(err).NotTo(HaveOccurred())
+		ctx := utils.WithMetricsRecorder(context.Background(), ctxRecorder)
+
+		indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+		getter := utils.NewListerNetworkGetter(listers.NewNetworkAttachmentDefinitionLister(indexer), nil)
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name: "pod1", Namespace: "default",
+			Annotations: map[string]string{v1.NetworkAttachmentAnnot: "[{"},
+		}}
+		_, err = utils.ResolveNetworkSelections(ctx, pod, getter, "")
+		Expect(err).To(HaveOccurred())
+		Expect(testutil.ToFloat64(ctxRecorder.annotationErrors.WithLabelValues("json"))).To(Equal(1.0))
+		Expect(testutil.ToFloat64(recorder.annotationErrors.WithLabelValues("json"))).To(Equal(0.0))
+	})
+
+	It("collects the networks of a lister", func() {
+		indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+		for _, nad := range []*v1.NetworkAttachmentDefinition{
+			{ObjectMeta: metav1.ObjectMeta{Name: "net1", Namespace: "default"},
+				Spec: v1.NetworkAttachmentDefinitionSpec{Config: `{"cniVersion": "1.0.0", "plugins": [{"type": "bridge"}, {"type": "tuning"}, {"type": "tuning"}]}`}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "net2", Namespace: "default"},
+				Spec: v1.NetworkAttachmentDefinitionSpec{Config: `{"cniVersion": "1.0.0", "type": "bridge"}`}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "net3", Namespace: "infra"}},
+		} {
+			Expect(indexer.Add(nad)).To(Succeed())
+		}
+
+		collector := NewNetworkCollector(listers.NewNetworkAttachmentDefinitionLister(indexer))
+		Expect(registry.Register(collector)).To(Succeed())
+		Expect(testutil.CollectAndCompare(collector, strings.NewReader(`
+# HELP network_attachment_definition_client_networks Number of NetworkAttachmentDefinitions per namespace.
+# TYPE network_attachment_definition_client_networks gauge
+network_attachment_definition_client_networks{namespace="default"} 2
+network_attachment_definition_client_networks{namespace="infra"} 1
+# HELP network_attachment_definition_client_networks_by_plugin Number of NetworkAttachmentDefinitions using a CNI plugin type.
+# TYPE network_attachment_definition_client_networks_by_plugin gauge
+network_attachment_definition_client_networks_by_plugin{type="bridge"} 2
+network_attachment_definition_client_networks_by_plugin{type="tuning"} 1
+`))).To(Succeed())
+	})
+})
@@ -0,0 +1,156 @@
+// Copyright (c) 2024 Kubernetes Network Plumbing Working Group
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nadtemplate
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/util/retry"
+
+	v1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+	clientset "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/client/clientset/versioned"
+)
+
+// Reconciler materializes templates into NetworkAttachmentDefinitions
+type Reconciler struct {
+	client clientset.Interface
+}
+
+// NewReconciler creates a Reconciler using the given clientset
+func NewReconciler(client clientset.Interface) *Reconciler {
+	return &Reconciler{client: client}
+}
+
+// Sync makes the NetworkAttachmentDefinitions rendered from the template
+// match the targets: missing objects are created, drifted ones updated, and
+// objects previously rendered from the template that are no longer
+// targeted are deleted. Existing objects not rendered from the template are
+// never modified. When a target fails to render or is targeted more than
+// once, nothing is deleted, as the objects it renders are not known.
+func (r *Reconciler) Sync(ctx context.Context, tmpl *NetworkAttachmentDefinitionTemplate, targets []Target) error {
+	if tmpl == nil {
+		return fmt.Errorf("no template set")
+	}
+
+	desired := make(map[string]*v1.NetworkAttachmentDefinition)
+	var errs []error
+	complete := true
+	for _, target := range targets {
+		nad, err := Render(tmpl, target)
+		if err != nil {
+			errs = append(errs, err)
+			complete = false
+			continue
+		}
+		key := nad.Namespace + "/" + nad.Name
+		if _, ok := desired[key]; ok {
+			errs = append(errs, fmt.Errorf("template %s: %s is targeted more than once", tmpl.Name, key))
+			complete = false
+			continue
+		}
+		desired[key] = nad
+		if err := r.apply(ctx, tmpl, nad); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if !complete {
+		return utilerrors.NewAggregate(errs)
+	}
+
+	selector := labels.SelectorFromSet(labels.Set{TemplateLabel: tmpl.Name}).String()
+	existing, err := r.client.K8sCniCncfIoV1().NetworkAttachmentDefinitions(metav1.NamespaceAll).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		errs = append(errs, fmt.Errorf("template %s: failed to list rendered objects: %v", tmpl.Name, err))
+		return utilerrors.NewAggregate(errs)
+	}
+	for _, nad := range existing.Items {
+		if _, ok := desired[nad.Namespace+"/"+nad.Name]; ok {
+			continue
+		}
+		err := r.client.K8sCniCncfIoV1().NetworkAttachmentDefinitions(nad.Namespace).Delete(ctx, nad.Name, metav1.DeleteOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("template %s: failed to delete %s/%s: %v", tmpl.Name, nad.Namespace, nad.Name, err))
+		}
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+func (r *Reconciler) apply(ctx context.Context, tmpl *NetworkAttachmentDefinitionTemplate, desired *v1.NetworkAttachmentDefinition) error {
+	nads := r.client.K8sCniCncfIoV1().NetworkAttachmentDefinitions(desired.Namespace)
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		current, err := nads.Get(ctx, desired.Name, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			_, err = nads.Create(ctx, desired, metav1.CreateOptions{})
+			return err
+		}
+		if err != nil {
+			return err
+		}
+
+		if current.Labels[TemplateLabel] != tmpl.Name {
+			return fmt.Errorf("object exists and was not rendered from this template")
+		}
+		if !needsUpdate(current, desired) {
+			return nil
+		}
+
+		updated := current.DeepCopy()
+		updated.Spec = desired.Spec
+		if updated.Labels == nil {
+			updated.Labels = map[string]string{}
+		}
+		for k, v := range desired.Labels {
+			updated.Labels[k] = v
+		}
+		if len(desired.Annotations) > 0 && updated.Annotations == nil {
+			updated.Annotations = map[string]string{}
+		}
+		for k, v := range desired.Annotations {
+			updated.Annotations[k] = v
+		}
+		_, err = nads.Update(ctx, updated, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("template %s: failed to sync %s/%s: %v", tmpl.Name, desired.Namespace, desired.Name, err)
+	}
+	return nil
+}
+
+// needsUpdate reports whether the current object differs from the desired
+// one in its spec, or in the labels and annotations set by the template
+func needsUpdate(current, desired *v1.NetworkAttachmentDefinition) bool {
+	if current.Spec != desired.Spec {
+		return true
+	}
+	for k, v := range desired.Labels {
+		if current.Labels[k] != v {
+			return true
+		}
+	}
+	for k, v := range desired.Annotations {
+		if current.Annotations[k] != v {
+			return true
+		}
+	}
+	return false
+}
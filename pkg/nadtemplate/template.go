@@ -0,0 +1,157 @@
+// Copyright (c) 2024 Kubernetes Network Plumbing Working Group
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package nadtemplate renders NetworkAttachmentDefinitions from a shared
+// template and per target parameters, and keeps the rendered objects in
+// sync in their target namespaces.
+package nadtemplate
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"text/template"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+)
+
+const (
+	// TemplateLabel is set on every rendered NetworkAttachmentDefinition to
+	// the name of the template it was rendered from
+	TemplateLabel = "k8s.v1.cni.cncf.io/template"
+)
+
+// Parameters are the values a template is rendered with
+type Parameters map[string]interface{}
+
+// NetworkAttachmentDefinitionTemplate describes a family of
+// NetworkAttachmentDefinitions that only differ by a few parameters
+type NetworkAttachmentDefinitionTemplate struct {
+	// Name identifies the template and is the default name of rendered objects
+	Name string
+	// Config is a Go text/template producing the CNI JSON configuration
+	Config string
+	// Labels and Annotations are copied to every rendered object
+	Labels      map[string]string
+	Annotations map[string]string
+	// Defaults are merged under every target's parameters
+	Defaults Parameters
+}
+
+// Target is a NetworkAttachmentDefinition to materialize from a template
+type Target struct {
+	Namespace string
+	// Name overrides the template name when set
+	Name       string
+	Parameters Parameters
+	// ConfigPatch is an optional RFC 6902 JSON patch applied to the
+	// rendered config
+	ConfigPatch []byte
+}
+
+var funcMap = template.FuncMap{
+	// json renders a value as JSON, e.g. {{ json .ranges }}
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
+// RenderConfig executes a config template with the given parameters and
+// checks that the result is valid JSON. Missing parameters are errors.
+func RenderConfig(config string, params Parameters) ([]byte, error) {
+	tmpl, err := template.New("config").Funcs(funcMap).Option("missingkey=error").Parse(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config template: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, map[string]interface{}(params)); err != nil {
+		return nil, fmt.Errorf("failed to render config template: %v", err)
+	}
+
+	if !json.Valid(buf.Bytes()) {
+		return nil, fmt.Errorf("rendered config is not valid JSON: %s", buf.String())
+	}
+	return buf.Bytes(), nil
+}
+
+// Render creates the NetworkAttachmentDefinition for the target
+func Render(tmpl *NetworkAttachmentDefinitionTemplate, target Target) (*v1.NetworkAttachmentDefinition, error) {
+	if tmpl == nil {
+		return nil, fmt.Errorf("no template set")
+	}
+	if target.Namespace == "" {
+		return nil, fmt.Errorf("template %s: target has no namespace", tmpl.Name)
+	}
+
+	name := target.Name
+	if name == "" {
+		name = tmpl.Name
+	}
+
+	params := Parameters{}
+	for k, v := range tmpl.Defaults {
+		params[k] = v
+	}
+	for k, v := range target.Parameters {
+		params[k] = v
+	}
+	params["namespace"] = target.Namespace
+	params["name"] = name
+
+	config, err := RenderConfig(tmpl.Config, params)
+	if err != nil {
+		return nil, fmt.Errorf("template %s for %s/%s: %v", tmpl.Name, target.Namespace, name, err)
+	}
+
+	if len(target.ConfigPatch) > 0 {
+		patch, err := jsonpatch.DecodePatch(target.ConfigPatch)
+		if err != nil {
+			return nil, fmt.Errorf("template %s for %s/%s: invalid config patch: %v", tmpl.Name, target.Namespace, name, err)
+		}
+		if config, err = patch.Apply(config); err != nil {
+			return nil, fmt.Errorf("template %s for %s/%s: failed to apply config patch: %v", tmpl.Name, target.Namespace, name, err)
+		}
+	}
+
+	labels := map[string]string{}
+	for k, v := range tmpl.Labels {
+		labels[k] = v
+	}
+	labels[TemplateLabel] = tmpl.Name
+
+	var annotations map[string]string
+	if len(tmpl.Annotations) > 0 {
+		annotations = map[string]string{}
+		for k, v := range tmpl.Annotations {
+			annotations[k] = v
+		}
+	}
+
+	return &v1.NetworkAttachmentDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   target.Namespace,
+			Labels:      labels,
+			Annotations: annotations,
+		},
+		Spec: v1.NetworkAttachmentDefinitionSpec{
+			Config: string(config),
+		},
+	}, nil
+}
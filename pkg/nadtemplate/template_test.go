@@ -0,0 +1,142 @@
+// Copyright (c) 2024 Kubernetes Network Plumbing Working Group
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nadtemplate
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+	"github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/client/clientset/versioned/fake"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("NetworkAttachmentDefinition templates", func() {
+	var tmpl *NetworkAttachmentDefinitionTemplate
+
+	BeforeEach(func() {
+		tmpl = &NetworkAttachmentDefinitionTemplate{
+			Name: "vlan",
+			Config: `{
+				"cniVersion": "0.3.1",
+				"name": "{{ .name }}",
+				"type": "vlan",
+				"master": "{{ .master }}",
+				"vlanId": {{ .vlan }},
+				"ipam": {"type": "whereabouts", "range": "{{ .range }}"}
+			}`,
+			Labels:   map[string]string{"team": "net"},
+			Defaults: Parameters{"master": "eth1"},
+		}
+	})
+
+	It("renders a target with defaults and a config patch", func() {
+		nad, err := Render(tmpl, Target{
+			Namespace:   "tenant-a",
+			Parameters:  Parameters{"vlan": 100, "range": "10.100.0.0/24"},
+			ConfigPatch: []byte(`[{"op": "add", "path": "/mtu", "value": 9000}]`),
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(nad.Name).To(Equal("vlan"))
+		Expect(nad.Namespace).To(Equal("tenant-a"))
+		Expect(nad.Labels).To(Equal(map[string]string{"team": "net", TemplateLabel: "vlan"}))
+		Expect(nad.Spec.Config).To(MatchJSON(`{
+			"cniVersion": "0.3.1",
+			"name": "vlan",
+			"type": "vlan",
+			"master": "eth1",
+			"vlanId": 100,
+			"mtu": 9000,
+			"ipam": {"type": "whereabouts", "range": "10.100.0.0/24"}
+		}`))
+	})
+
+	It("fails on missing parameters", func() {
+		_, err := Render(tmpl, Target{Namespace: "tenant-a", Parameters: Parameters{"vlan": 100}})
+		Expect(err).To(MatchError(ContainSubstring("range")))
+	})
+
+	It("fails when the rendered config is not JSON", func() {
+		_, err := RenderConfig(`{"vlanId": {{ .vlan }}`, Parameters{"vlan": 1})
+		Expect(err).To(MatchError(ContainSubstring("not valid JSON")))
+	})
+
+	It("creates, updates and prunes rendered objects", func() {
+		unmanaged := &v1.NetworkAttachmentDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "vlan", Namespace: "tenant-c"},
+			Spec:       v1.NetworkAttachmentDefinitionSpec{Config: `{}`},
+		}
+		client := fake.NewSimpleClientset()
+		reconciler := NewReconciler(client)
+		ctx := context.TODO()
+		nad, err := client.K8sCniCncfIoV1().NetworkAttachmentDefinitions("tenant-c").Create(ctx, unmanaged, metav1.CreateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		targets := []Target{
+			{Namespace: "tenant-a", Parameters: Parameters{"vlan": 100, "range": "10.100.0.0/24"}},
+			{Namespace: "tenant-b", Parameters: Parameters{"vlan": 200, "range": "10.200.0.0/24"}},
+		}
+		Expect(reconciler.Sync(ctx, tmpl, targets)).To(Succeed())
+
+		nad, err = client.K8sCniCncfIoV1().NetworkAttachmentDefinitions("tenant-b").Get(ctx, "vlan", metav1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(nad.Spec.Config).To(ContainSubstring(`"vlanId": 200`))
+
+		targets = []Target{
+			{Namespace: "tenant-a", Parameters: Parameters{"vlan": 101, "range": "10.100.0.0/24"}},
+		}
+		Expect(reconciler.Sync(ctx, tmpl, targets)).To(Succeed())
+
+		nad, err = client.K8sCniCncfIoV1().NetworkAttachmentDefinitions("tenant-a").Get(ctx, "vlan", metav1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(nad.Spec.Config).To(ContainSubstring(`"vlanId": 101`))
+
+		_, err = client.K8sCniCncfIoV1().NetworkAttachmentDefinitions("tenant-b").Get(ctx, "vlan", metav1.GetOptions{})
+		Expect(err).To(HaveOccurred())
+
+		err = reconciler.Sync(ctx, tmpl, []Target{{Namespace: "tenant-c", Parameters: Parameters{"vlan": 300, "range": "10.30.0.0/24"}}})
+		Expect(err).To(MatchError(ContainSubstring("not rendered from this template")))
+		nad, err = client.K8sCniCncfIoV1().NetworkAttachmentDefinitions("tenant-c").Get(ctx, "vlan", metav1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(nad.Spec.Config).To(Equal(`{}`))
+	})
+
+	It("does not prune when a target fails to render", func() {
+		client := fake.NewSimpleClientset()
+		reconciler := NewReconciler(client)
+		ctx := context.TODO()
+
+		targets := []Target{
+			{Namespace: "tenant-a", Parameters: Parameters{"vlan": 100, "range": "10.100.0.0/24"}},
+			{Namespace: "tenant-b", Parameters: Parameters{"vlan": 200, "range": "10.200.0.0/24"}},
+		}
+		Expect(reconciler.Sync(ctx, tmpl, targets)).To(Succeed())
+
+		targets[1].Parameters = Parameters{"vlan": 200}
+		err := reconciler.Sync(ctx, tmpl, targets)
+		Expect(err).To(MatchError(ContainSubstring("range")))
+		_, err = client.K8sCniCncfIoV1().NetworkAttachmentDefinitions("tenant-b").Get(ctx, "vlan", metav1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		targets = []Target{targets[0], targets[0]}
+		err = reconciler.Sync(ctx, tmpl, targets)
+		Expect(err).To(MatchError(ContainSubstring("targeted more than once")))
+		_, err = client.K8sCniCncfIoV1().NetworkAttachmentDefinitions("tenant-b").Get(ctx, "vlan", metav1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
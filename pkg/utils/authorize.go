@@ -0,0 +1,107 @@
+// Copyright (c) 2024 Kubernetes Network Plumbing Working Group
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	corelisters "k8s.io/client-go/listers/core/v1"
+
+	v1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+)
+
+// AuthorizeSelection checks that the pod may attach to the network selected
+// by sel. Pods may always attach to networks in their own namespace. Other
+// namespaced networks must explicitly allow the pod's namespace through the
+// AllowedNamespacesAnnot or AllowedNamespaceSelectorAnnot annotations.
+// Cluster networks allow every namespace unless one of these annotations is
+// set. The network must be the one sel references: a cluster network for
+// cluster-scoped selections, and the network of sel's namespace or, as
+// plain references fall back to them, a cluster network otherwise. A
+// Forbidden error is returned when the attachment is denied.
+func AuthorizeSelection(pod *corev1.Pod, sel *v1.NetworkSelectionElement, nad *v1.NetworkAttachmentDefinition, nsLister corelisters.NamespaceLister) error {
+	if pod == nil {
+		return fmt.Errorf("no pod set")
+	}
+	if sel == nil {
+		return fmt.Errorf("no network selection element set")
+	}
+	if nad == nil {
+		return fmt.Errorf("no network attachment definition set")
+	}
+	if nad.Name != sel.Name ||
+		(sel.Scope == v1.NetworkScopeCluster && nad.Namespace != "") ||
+		(nad.Namespace != "" && nad.Namespace != sel.Namespace) {
+		return fmt.Errorf("network %s is not the network selected by %s", nadRef(nad), selectionRef(sel))
+	}
+
+	if nad.Namespace == pod.Namespace {
+		return nil
+	}
+
+	allowedNamespaces, hasAllowedNamespaces := nad.Annotations[v1.AllowedNamespacesAnnot]
+	selectorText, hasSelector := nad.Annotations[v1.AllowedNamespaceSelectorAnnot]
+	if nad.Namespace == "" && !hasAllowedNamespaces && !hasSelector {
+		return nil
+	}
+
+	if hasAllowedNamespaces {
+		for _, ns := range strings.Split(allowedNamespaces, ",") {
+			ns = strings.TrimSpace(ns)
+			if ns == "*" || ns == pod.Namespace {
+				return nil
+			}
+		}
+	}
+
+	if hasSelector {
+		selector, err := labels.Parse(selectorText)
+		if err != nil {
+			return forbidden(pod, sel, nad, fmt.Sprintf("invalid %s annotation: %v", v1.AllowedNamespaceSelectorAnnot, err))
+		}
+		if nsLister == nil {
+			return fmt.Errorf("no namespace lister set to evaluate %s", v1.AllowedNamespaceSelectorAnnot)
+		}
+		ns, err := nsLister.Get(pod.Namespace)
+		if err != nil {
+			return fmt.Errorf("failed to get namespace %s: %v", pod.Namespace, err)
+		}
+		if selector.Matches(labels.Set(ns.Labels)) {
+			return nil
+		}
+	}
+
+	return forbidden(pod, sel, nad, fmt.Sprintf("namespace %s is not allowed to attach to it", pod.Namespace))
+}
+
+func forbidden(pod *corev1.Pod, sel *v1.NetworkSelectionElement, nad *v1.NetworkAttachmentDefinition, reason string) error {
+	resource := "network-attachment-definitions"
+	if nad.Namespace == "" {
+		resource = "cluster-network-attachment-definitions"
+	}
+	return errors.NewForbidden(v1.Resource(resource), nadRef(nad),
+		fmt.Errorf("pod %s/%s selecting %s: %s", pod.Namespace, pod.Name, selectionRef(sel), reason))
+}
+
+func nadRef(nad *v1.NetworkAttachmentDefinition) string {
+	if nad.Namespace == "" {
+		return nad.Name
+	}
+	return nad.Namespace + "/" + nad.Name
+}
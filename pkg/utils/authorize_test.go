@@ -0,0 +1,116 @@
+// Copyright (c) 2024 Kubernetes Network Plumbing Working Group
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	v1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Network attachment authorization", func() {
+	var nsLister corelisters.NamespaceLister
+	var pod *corev1.Pod
+
+	newNAD := func(namespace string, annotations map[string]string) *v1.NetworkAttachmentDefinition {
+		return &v1.NetworkAttachmentDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "net1", Namespace: namespace, Annotations: annotations},
+		}
+	}
+
+	BeforeEach(func() {
+		indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+		Expect(indexer.Add(&corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: "tenant", Labels: map[string]string{"team": "blue"}},
+		})).To(Succeed())
+		nsLister = corelisters.NewNamespaceLister(indexer)
+		pod = &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod1", Namespace: "tenant"}}
+	})
+
+	It("allows networks in the pod namespace", func() {
+		nad := newNAD("tenant", nil)
+		sel := &v1.NetworkSelectionElement{Name: "net1", Namespace: "tenant"}
+		Expect(AuthorizeSelection(pod, sel, nad, nsLister)).To(Succeed())
+	})
+
+	It("denies cross-namespace networks without a policy", func() {
+		nad := newNAD("infra", nil)
+		sel := &v1.NetworkSelectionElement{Name: "net1", Namespace: "infra"}
+		err := AuthorizeSelection(pod, sel, nad, nsLister)
+		Expect(errors.IsForbidden(err)).To(BeTrue())
+	})
+
+	It("allows cross-namespace networks listing the pod namespace", func() {
+		sel := &v1.NetworkSelectionElement{Name: "net1", Namespace: "infra"}
+		nad := newNAD("infra", map[string]string{v1.AllowedNamespacesAnnot: "other, tenant"})
+		Expect(AuthorizeSelection(pod, sel, nad, nsLister)).To(Succeed())
+
+		nad = newNAD("infra", map[string]string{v1.AllowedNamespacesAnnot: "*"})
+		Expect(AuthorizeSelection(pod, sel, nad, nsLister)).To(Succeed())
+
+		nad = newNAD("infra", map[string]string{v1.AllowedNamespacesAnnot: "other"})
+		Expect(errors.IsForbidden(AuthorizeSelection(pod, sel, nad, nsLister))).To(BeTrue())
+	})
+
+	It("allows cross-namespace networks selecting the pod namespace", func() {
+		sel := &v1.NetworkSelectionElement{Name: "net1", Namespace: "infra"}
+		nad := newNAD("infra", map[string]string{v1.AllowedNamespaceSelectorAnnot: "team in (blue,green)"})
+		Expect(AuthorizeSelection(pod, sel, nad, nsLister)).To(Succeed())
+
+		nad = newNAD("infra", map[string]string{v1.AllowedNamespaceSelectorAnnot: "team=red"})
+		Expect(errors.IsForbidden(AuthorizeSelection(pod, sel, nad, nsLister))).To(BeTrue())
+
+		nad = newNAD("infra", map[string]string{v1.AllowedNamespaceSelectorAnnot: "team in (blue"})
+		Expect(errors.IsForbidden(AuthorizeSelection(pod, sel, nad, nsLister))).To(BeTrue())
+	})
+
+	It("allows cluster networks unless they restrict namespaces", func() {
+		sel := &v1.NetworkSelectionElement{Name: "net1", Scope: v1.NetworkScopeCluster}
+		Expect(AuthorizeSelection(pod, sel, newNAD("", nil), nsLister)).To(Succeed())
+
+		nad := newNAD("", map[string]string{v1.AllowedNamespacesAnnot: "infra"})
+		Expect(errors.IsForbidden(AuthorizeSelection(pod, sel, nad, nsLister))).To(BeTrue())
+	})
+
+	It("rejects networks other than the selected one", func() {
+		sel := &v1.NetworkSelectionElement{Name: "net1", Scope: v1.NetworkScopeCluster}
+		err := AuthorizeSelection(pod, sel, newNAD("tenant", nil), nsLister)
+		Expect(err).To(MatchError("network tenant/net1 is not the network selected by cluster:net1"))
+
+		sel = &v1.NetworkSelectionElement{Name: "net2", Namespace: "tenant"}
+		Expect(AuthorizeSelection(pod, sel, newNAD("tenant", nil), nsLister)).NotTo(Succeed())
+
+		sel = &v1.NetworkSelectionElement{Name: "net1", Namespace: "infra"}
+		Expect(AuthorizeSelection(pod, sel, newNAD("tenant", nil), nsLister)).NotTo(Succeed())
+	})
+
+	It("allows plain references falling back to cluster networks", func() {
+		sel := &v1.NetworkSelectionElement{Name: "net1", Namespace: "tenant"}
+		Expect(AuthorizeSelection(pod, sel, newNAD("", nil), nsLister)).To(Succeed())
+	})
+
+	It("names the selection in denials", func() {
+		sel := &v1.NetworkSelectionElement{Name: "net1", Namespace: "infra"}
+		err := AuthorizeSelection(pod, sel, newNAD("infra", nil), nsLister)
+		Expect(err).To(MatchError(ContainSubstring("pod tenant/pod1 selecting infra/net1: namespace tenant is not allowed")))
+	})
+})
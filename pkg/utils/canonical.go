@@ -0,0 +1,148 @@
+// Copyright (c) 2024 Kubernetes Network Plumbing Working Group
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// CanonicalizeConfig returns the stable form of a JSON CNI configuration:
+// compact, with the object keys sorted, HTML characters left unescaped and
+// numbers in their shortest exact decimal form, so 1.5e3, 1500.0 and 1500
+// all become 1500. Numbers are never converted to floating point, so large
+// integers are preserved. Configurations that are equal as JSON documents
+// have the same canonical form.
+func CanonicalizeConfig(config []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(config))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config: %v", err)
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, fmt.Errorf("failed to unmarshal config: unexpected data after the JSON value")
+	}
+	return marshalCanonical(value)
+}
+
+// decodeConfig decodes a JSON config object, keeping numbers as json.Number
+func decodeConfig(config []byte) (map[string]interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(config))
+	decoder.UseNumber()
+	var rawConfig map[string]interface{}
+	if err := decoder.Decode(&rawConfig); err != nil {
+		return nil, err
+	}
+	if rawConfig == nil {
+		return nil, fmt.Errorf("config is not a JSON object")
+	}
+	return rawConfig, nil
+}
+
+// marshalCanonical marshals a value decoded with json.Number in the canonical
+// form of CanonicalizeConfig. encoding/json already sorts the keys of maps.
+func marshalCanonical(value interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(canonicalValue(value)); err != nil {
+		return nil, fmt.Errorf("failed to marshal config: %v", err)
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+func canonicalValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		return json.Number(canonicalNumber(string(v)))
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			out[k] = canonicalValue(item)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = canonicalValue(item)
+		}
+		return out
+	default:
+		return value
+	}
+}
+
+// canonicalNumber rewrites a JSON number in its shortest exact decimal form:
+// integers, unless they end with more than 21 zeros, and decimals down to
+// 1e-6 are written out, other numbers in exponent notation. Numbers it cannot
+// parse are returned unchanged.
+func canonicalNumber(number string) string {
+	negative := strings.HasPrefix(number, "-")
+	mantissa := strings.TrimPrefix(number, "-")
+
+	exponent := 0
+	if i := strings.IndexAny(mantissa, "eE"); i >= 0 {
+		e, err := strconv.Atoi(strings.TrimPrefix(mantissa[i+1:], "+"))
+		if err != nil {
+			return number
+		}
+		exponent = e
+		mantissa = mantissa[:i]
+	}
+	digits := mantissa
+	if i := strings.IndexByte(mantissa, '.'); i >= 0 {
+		digits = mantissa[:i] + mantissa[i+1:]
+		exponent -= len(mantissa) - i - 1
+	}
+
+	digits = strings.TrimLeft(digits, "0")
+	if digits == "" {
+		return "0"
+	}
+	trimmed := strings.TrimRight(digits, "0")
+	exponent += len(digits) - len(trimmed)
+	digits = trimmed
+
+	// point is the position of the decimal point from the first digit
+	point := len(digits) + exponent
+	var out string
+	switch {
+	case exponent >= 0 && exponent <= 21:
+		out = digits + strings.Repeat("0", exponent)
+	case exponent < 0 && point > 0:
+		out = digits[:point] + "." + digits[point:]
+	case exponent < 0 && point > -6:
+		out = "0." + strings.Repeat("0", -point) + digits
+	default:
+		out = digits[:1]
+		if len(digits) > 1 {
+			out += "." + digits[1:]
+		}
+		out += "e"
+		if point-1 > 0 {
+			out += "+"
+		}
+		out += strconv.Itoa(point - 1)
+	}
+	if negative {
+		out = "-" + out
+	}
+	return out
+}
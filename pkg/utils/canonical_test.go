@@ -0,0 +1,59 @@
+// Copyright (c) 2024 Kubernetes Network Plumbing Working Group
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Canonical configs", func() {
+	table.DescribeTable("canonicalizes configs",
+		func(config, expected string) {
+			canonical, err := CanonicalizeConfig([]byte(config))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(canonical)).To(Equal(expected))
+
+			// the canonical form is stable
+			again, err := CanonicalizeConfig(canonical)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(again).To(Equal(canonical))
+		},
+		table.Entry("sorts keys and drops whitespace",
+			`{ "type": "bridge", "name": "net1",
+			   "ipam": {"type": "host-local", "ranges": [[{"subnet": "10.1.0.0/24"}]]} }`,
+			`{"ipam":{"ranges":[[{"subnet":"10.1.0.0/24"}]],"type":"host-local"},"name":"net1","type":"bridge"}`),
+		table.Entry("keeps HTML characters", `{"args": "<a&b>"}`, `{"args":"<a&b>"}`),
+		table.Entry("writes integers out", `[1500, 1500.0, 1.5e3, 15E2, 150000e-2, -0.0, 0e10]`, `[1500,1500,1500,1500,1500,0,0]`),
+		table.Entry("preserves large integers", `{"id": 18446744073709551617, "big": 1234567890123456789012345}`, `{"big":1234567890123456789012345,"id":18446744073709551617}`),
+		table.Entry("writes decimals out", `[0.50, 12.340e1, 1e-6, -2.5e-3]`, `[0.5,123.4,0.000001,-0.0025]`),
+		table.Entry("uses exponents for very large and small numbers", `[1e21, 1.0e22, 12e-8, -3e-7]`, `[1000000000000000000000,1e+22,1.2e-7,-3e-7]`),
+	)
+
+	It("fails on invalid JSON", func() {
+		_, err := CanonicalizeConfig([]byte(`{"type": "bridge"`))
+		Expect(err).To(HaveOccurred())
+
+		_, err = CanonicalizeConfig([]byte(`{"type": "bridge"} {}`))
+		Expect(err).To(MatchError(ContainSubstring("unexpected data")))
+	})
+
+	It("injects the network name in canonical form", func() {
+		config, err := GetCNIConfigFromSpec(`{"type": "vlan", "vlanId": 1.0e2, "cookie": 9007199254740993}`, "net1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(config)).To(Equal(`{"cookie":9007199254740993,"name":"net1","type":"vlan","vlanId":100}`))
+	})
+})
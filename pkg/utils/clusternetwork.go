@@ -0,0 +1,72 @@
+// Copyright (c) 2024 Kubernetes Network Plumbing Working Group
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	v1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+	listers "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/client/listers/k8s.cni.cncf.io/v1"
+)
+
+// ClusterNetworkToNetworkAttachmentDefinition returns the cluster network as
+// a NetworkAttachmentDefinition without a namespace, so it can be used with
+// the NetworkAttachmentDefinition helpers such as GetCNIConfig
+func ClusterNetworkToNetworkAttachmentDefinition(cnet *v1.ClusterNetworkAttachmentDefinition) *v1.NetworkAttachmentDefinition {
+	cnet = cnet.DeepCopy()
+	nad := &v1.NetworkAttachmentDefinition{
+		ObjectMeta: cnet.ObjectMeta,
+		Spec:       cnet.Spec,
+	}
+	nad.Namespace = ""
+	return nad
+}
+
+// ResolveNetworkAttachmentDefinition returns the network referenced by the
+// network selection element. Cluster references (Scope set to
+// NetworkScopeCluster) only resolve to ClusterNetworkAttachmentDefinitions;
+// other references are looked up in the element's namespace first and fall
+// back to a ClusterNetworkAttachmentDefinition of the same name. The cluster
+// lister may be nil to disable cluster networks. A NotFound error is
+// returned when the network does not exist.
+func ResolveNetworkAttachmentDefinition(sel *v1.NetworkSelectionElement, nadLister listers.NetworkAttachmentDefinitionLister, clusterLister listers.ClusterNetworkAttachmentDefinitionLister) (*v1.NetworkAttachmentDefinition, error) {
+	if sel == nil {
+		return nil, fmt.Errorf("no network selection element set")
+	}
+
+	if sel.Scope != v1.NetworkScopeCluster {
+		if nadLister == nil {
+			return nil, fmt.Errorf("no network attachment definition lister set")
+		}
+		nad, err := nadLister.NetworkAttachmentDefinitions(sel.Namespace).Get(sel.Name)
+		if err == nil {
+			return nad.DeepCopy(), nil
+		}
+		if !errors.IsNotFound(err) || clusterLister == nil {
+			return nil, fmt.Errorf("failed to get network %s/%s: %w", sel.Namespace, sel.Name, err)
+		}
+	}
+
+	if clusterLister == nil {
+		return nil, fmt.Errorf("cluster network %s requested but no cluster network lister set", sel.Name)
+	}
+	cnet, err := clusterLister.Get(sel.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cluster network %s: %w", sel.Name, err)
+	}
+	return ClusterNetworkToNetworkAttachmentDefinition(cnet), nil
+}
@@ -0,0 +1,97 @@
+// Copyright (c) 2024 Kubernetes Network Plumbing Working Group
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	v1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+	listers "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/client/listers/k8s.cni.cncf.io/v1"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Cluster network resolution", func() {
+	var nadLister listers.NetworkAttachmentDefinitionLister
+	var clusterLister listers.ClusterNetworkAttachmentDefinitionLister
+
+	BeforeEach(func() {
+		nadIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+		Expect(nadIndexer.Add(&v1.NetworkAttachmentDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "shared", Namespace: "tenant"},
+			Spec:       v1.NetworkAttachmentDefinitionSpec{Config: `{"type": "namespaced"}`},
+		})).To(Succeed())
+		nadLister = listers.NewNetworkAttachmentDefinitionLister(nadIndexer)
+
+		clusterIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+		Expect(clusterIndexer.Add(&v1.ClusterNetworkAttachmentDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "shared"},
+			Spec:       v1.NetworkAttachmentDefinitionSpec{Config: `{"type": "cluster"}`},
+		})).To(Succeed())
+		Expect(clusterIndexer.Add(&v1.ClusterNetworkAttachmentDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "platform"},
+			Spec:       v1.NetworkAttachmentDefinitionSpec{Config: `{"type": "cluster"}`},
+		})).To(Succeed())
+		clusterLister = listers.NewClusterNetworkAttachmentDefinitionLister(clusterIndexer)
+	})
+
+	It("parses explicit cluster references", func() {
+		networks, err := ParseNetworkAnnotation("cluster:platform@ext0, shared", "tenant")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(networks).To(HaveLen(2))
+		Expect(networks[0].Name).To(Equal("platform"))
+		Expect(networks[0].Namespace).To(BeEmpty())
+		Expect(networks[0].Scope).To(Equal(v1.NetworkScopeCluster))
+		Expect(networks[0].InterfaceRequest).To(Equal("ext0"))
+		Expect(networks[1].Namespace).To(Equal("tenant"))
+		Expect(networks[1].Scope).To(BeEmpty())
+
+		networks, err = ParseNetworkAnnotation(`[{"name": "platform", "scope": "cluster"}]`, "tenant")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(networks[0].Namespace).To(BeEmpty())
+
+		_, err = ParseNetworkAnnotation("cluster:other/platform", "tenant")
+		Expect(err).To(HaveOccurred())
+		_, err = ParseNetworkAnnotation(`[{"name": "platform", "scope": "galaxy"}]`, "tenant")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("prefers the namespaced network and falls back to the cluster network", func() {
+		nad, err := ResolveNetworkAttachmentDefinition(&v1.NetworkSelectionElement{Name: "shared", Namespace: "tenant"}, nadLister, clusterLister)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(nad.Namespace).To(Equal("tenant"))
+		Expect(nad.Spec.Config).To(Equal(`{"type": "namespaced"}`))
+
+		nad, err = ResolveNetworkAttachmentDefinition(&v1.NetworkSelectionElement{Name: "platform", Namespace: "tenant"}, nadLister, clusterLister)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(nad.Name).To(Equal("platform"))
+		Expect(nad.Namespace).To(BeEmpty())
+	})
+
+	It("resolves explicit cluster references only to cluster networks", func() {
+		nad, err := ResolveNetworkAttachmentDefinition(&v1.NetworkSelectionElement{Name: "shared", Scope: v1.NetworkScopeCluster}, nadLister, clusterLister)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(nad.Spec.Config).To(Equal(`{"type": "cluster"}`))
+
+		_, err = ResolveNetworkAttachmentDefinition(&v1.NetworkSelectionElement{Name: "missing", Namespace: "tenant"}, nadLister, clusterLister)
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+
+		_, err = ResolveNetworkAttachmentDefinition(&v1.NetworkSelectionElement{Name: "platform", Namespace: "tenant"}, nadLister, nil)
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+	})
+})
@@ -15,13 +15,16 @@
 package utils
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/containernetworking/cni/libcni"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	v1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
 )
@@ -33,15 +36,23 @@ const (
 )
 
 // GetCNIConfig (from annotation string to CNI JSON bytes)
-func GetCNIConfig(net *v1.NetworkAttachmentDefinition, confDir string) (config []byte, err error) {
+// Errors wrap a *NetworkNotFoundError or an *InvalidConfigError when the
+// configuration is missing or malformed.
+func GetCNIConfig(net *v1.NetworkAttachmentDefinition, confDir string) ([]byte, error) {
+	return GetCNIConfigWithContext(context.Background(), net, confDir)
+}
+
+// GetCNIConfigWithContext is GetCNIConfig, logging and recording the config
+// file lookups to the logger and metrics recorder of ctx
+func GetCNIConfigWithContext(ctx context.Context, net *v1.NetworkAttachmentDefinition, confDir string) (config []byte, err error) {
 	emptySpec := v1.NetworkAttachmentDefinitionSpec{}
 	if net.Spec == emptySpec {
 		// Network Spec empty; generate delegate from CNI JSON config
 		// from the configuration directory that has the same network
 		// name as the custom resource
-		config, err = GetCNIConfigFromFile(net.Name, confDir)
+		config, err = GetCNIConfigFromFileWithContext(ctx, net.Name, confDir)
 		if err != nil {
-			return nil, fmt.Errorf("GetCNIConfig: err in GetCNIConfigFromFile: %v", err)
+			return nil, fmt.Errorf("GetCNIConfig: err in GetCNIConfigFromFile: %w", err)
 		}
 	} else {
 		// Config contains a standard JSON-encoded CNI configuration
@@ -49,14 +60,30 @@ func GetCNIConfig(net *v1.NetworkAttachmentDefinition, confDir string) (config [
 		// execute.
 		config, err = GetCNIConfigFromSpec(net.Spec.Config, net.Name)
 		if err != nil {
-			return nil, fmt.Errorf("GetCNIConfig: err in getCNIConfigFromSpec: %v", err)
+			return nil, fmt.Errorf("GetCNIConfig: err in getCNIConfigFromSpec: %w", err)
 		}
 	}
 	return config, nil
 }
 
 // GetCNIConfigFromSpec reads a CNI JSON configuration from given directory (confDir)
+// It returns a *NetworkNotFoundError when no file defines the network and an
+// *InvalidConfigError when a file is malformed or has invalid CNI 1.1
+// settings, see ParseCNIConfigInfo.
 func GetCNIConfigFromFile(name, confDir string) ([]byte, error) {
+	return GetCNIConfigFromFileWithContext(context.Background(), name, confDir)
+}
+
+// GetCNIConfigFromFileWithContext is GetCNIConfigFromFile, logging and
+// recording the lookup to the logger and metrics recorder of ctx
+func GetCNIConfigFromFileWithContext(ctx context.Context, name, confDir string) ([]byte, error) {
+	start := time.Now()
+	config, err := getCNIConfigFromFile(ctx, name, confDir)
+	recorderFrom(ctx).ObserveConfigFileLoad(time.Since(start), err)
+	return config, err
+}
+
+func getCNIConfigFromFile(ctx context.Context, name, confDir string) ([]byte, error) {
 	// In the absence of valid keys in a Spec, the runtime (or
 	// meta-plugin) should load and execute a CNI .configlist
 	// or .config (in that order) file on-disk whose JSON
@@ -66,69 +93,194 @@ func GetCNIConfigFromFile(name, confDir string) ([]byte, error) {
 	files, err := libcni.ConfFiles(confDir, []string{".conf", ".json", ".conflist"})
 	switch {
 	case err != nil:
-		return nil, fmt.Errorf("No networks found in %s", confDir)
+		return nil, &NetworkNotFoundError{Name: name, ConfDir: confDir, NoNetworks: true, Err: err}
 	case len(files) == 0:
-		return nil, fmt.Errorf("No networks found in %s", confDir)
+		return nil, &NetworkNotFoundError{Name: name, ConfDir: confDir, NoNetworks: true}
 	}
 
 	for _, confFile := range files {
-		var confList *libcni.NetworkConfigList
-		if strings.HasSuffix(confFile, ".conflist") {
-			confList, err = libcni.ConfListFromFile(confFile)
-			if err != nil {
-				return nil, fmt.Errorf("Error loading CNI conflist file %s: %v", confFile, err)
-			}
-
-			if confList.Name == name || name == "" {
-				return confList.Bytes, nil
-			}
-
-		} else {
-			conf, err := libcni.ConfFromFile(confFile)
-			if err != nil {
-				return nil, fmt.Errorf("Error loading CNI config file %s: %v", confFile, err)
-			}
-
-			if conf.Network.Name == name || name == "" {
-				// Ensure the config has a "type" so we know what plugin to run.
-				// Also catches the case where somebody put a conflist into a conf file.
-				if conf.Network.Type == "" {
-					return nil, fmt.Errorf("Error loading CNI config file %s: no 'type'; perhaps this is a .conflist?", confFile)
-				}
-				return conf.Bytes, nil
-			}
+		netName, configBytes, err := loadCNIConfigFile(confFile)
+		if err != nil {
+			return nil, err
+		}
+		if netName == name || name == "" {
+			return configBytes, nil
 		}
+		loggerFrom(ctx).V(logLevelDebug).Info("Skipping CNI config file of another network",
+			"file", confFile, "network", netName, "name", name)
 	}
 
-	return nil, fmt.Errorf("no network available in the name %s in cni dir %s", name, confDir)
+	return nil, &NetworkNotFoundError{Name: name, ConfDir: confDir}
+}
+
+// loadCNIConfigFile loads a CNI .conflist or .conf/.json file and returns
+// its network name along with the raw configuration
+func loadCNIConfigFile(confFile string) (string, []byte, error) {
+	if strings.HasSuffix(confFile, ".conflist") {
+		confList, err := libcni.ConfListFromFile(confFile)
+		if err != nil {
+			return "", nil, &InvalidConfigError{Path: confFile, Err: err}
+		}
+		if err := checkCNIConfigInfo(confList.Bytes); err != nil {
+			return "", nil, &InvalidConfigError{Path: confFile, Err: err}
+		}
+		return confList.Name, confList.Bytes, nil
+	}
+
+	conf, err := libcni.ConfFromFile(confFile)
+	if err != nil {
+		return "", nil, &InvalidConfigError{Path: confFile, Err: err}
+	}
+	// Ensure the config has a "type" so we know what plugin to run.
+	// Also catches the case where somebody put a conflist into a conf file.
+	if conf.Network.Type == "" {
+		return "", nil, &InvalidConfigError{Path: confFile, Err: errors.New("no 'type'; perhaps this is a .conflist?")}
+	}
+	if err := checkCNIConfigInfo(conf.Bytes); err != nil {
+		return "", nil, &InvalidConfigError{Path: confFile, Err: err}
+	}
+	return conf.Network.Name, conf.Bytes, nil
 }
 
 // GetCNIConfigFromSpec reads a CNI JSON configuration from the NetworkAttachmentDefinition
-// object's Spec.Config field and fills in any missing details like the network name
+// object's Spec.Config field and fills in any missing details like the network name.
+// The CNI 1.1 settings of the configuration are validated, see ParseCNIConfigInfo.
+// Configurations that are changed are returned in their canonical form, see
+// CanonicalizeConfig.
 func GetCNIConfigFromSpec(configData, netName string) ([]byte, error) {
-	var rawConfig map[string]interface{}
-	var err error
-
 	configBytes := []byte(configData)
-	err = json.Unmarshal(configBytes, &rawConfig)
+	rawConfig, err := decodeConfig(configBytes)
 	if err != nil {
-		return nil, fmt.Errorf("failed to unmarshal Spec.Config: %v", err)
+		return nil, &InvalidConfigError{Err: fmt.Errorf("failed to unmarshal Spec.Config: %w", err)}
 	}
 
 	// Inject network name if missing from Config for the thick plugin case
 	if n, ok := rawConfig["name"]; !ok || n == "" {
 		rawConfig["name"] = netName
-		configBytes, err = json.Marshal(rawConfig)
+		configBytes, err = marshalCanonical(rawConfig)
 		if err != nil {
 			return nil, fmt.Errorf("failed to re-marshal Spec.Config: %v", err)
 		}
 	}
+	if err := checkCNIConfigInfo(configBytes); err != nil {
+		return nil, &InvalidConfigError{Err: fmt.Errorf("invalid Spec.Config: %w", err)}
+	}
 
 	return configBytes, nil
 }
 
+// GetCNINetworkConfigList returns the NetworkAttachmentDefinition's CNI
+// configuration as a libcni NetworkConfigList, ready to be passed to
+// libcni.CNIConfig.AddNetworkList. Single plugin configurations are
+// converted into a list with that plugin as the only entry.
+func GetCNINetworkConfigList(net *v1.NetworkAttachmentDefinition, confDir string) (*libcni.NetworkConfigList, error) {
+	return GetCNINetworkConfigListWithVersion(net, confDir, "")
+}
+
+// GetCNINetworkConfigListWithVersion is like GetCNINetworkConfigList but
+// overrides the configuration's cniVersion when cniVersion is not empty
+func GetCNINetworkConfigListWithVersion(net *v1.NetworkAttachmentDefinition, confDir, cniVersion string) (*libcni.NetworkConfigList, error) {
+	config, err := GetCNIConfig(net, confDir)
+	if err != nil {
+		return nil, err
+	}
+
+	// Configurations are decoded without converting numbers to floating
+	// point, which would corrupt large integers
+	rawConfig, err := decodeConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("GetCNINetworkConfigList: failed to unmarshal config: %v", err)
+	}
+
+	changed := false
+	if cniVersion != "" {
+		rawConfig["cniVersion"] = cniVersion
+		changed = true
+	}
+	if _, ok := rawConfig["plugins"]; !ok {
+		if _, err := libcni.ConfFromBytes(config); err != nil {
+			return nil, fmt.Errorf("GetCNINetworkConfigList: %v", err)
+		}
+		rawConfig = singlePluginList(rawConfig)
+		changed = true
+	}
+	if changed {
+		config, err = marshalCanonical(rawConfig)
+		if err != nil {
+			return nil, fmt.Errorf("GetCNINetworkConfigList: failed to re-marshal config: %v", err)
+		}
+	}
+
+	if err := checkCNIConfigInfo(config); err != nil {
+		return nil, fmt.Errorf("GetCNINetworkConfigList: %w", err)
+	}
+	confList, err := libcni.ConfListFromBytes(config)
+	if err != nil {
+		return nil, fmt.Errorf("GetCNINetworkConfigList: %v", err)
+	}
+	return confList, nil
+}
+
+// PluginTypes returns the distinct plugin types of a CNI config or config
+// list, in the order of the plugins. It returns nil for an empty or
+// malformed config.
+func PluginTypes(config string) []string {
+	var conf struct {
+		Type    string `json:"type"`
+		Plugins []struct {
+			Type string `json:"type"`
+		} `json:"plugins"`
+	}
+	if config == "" || json.Unmarshal([]byte(config), &conf) != nil {
+		return nil
+	}
+	if conf.Type != "" {
+		return []string{conf.Type}
+	}
+	seen := map[string]bool{}
+	var types []string
+	for _, p := range conf.Plugins {
+		if p.Type != "" && !seen[p.Type] {
+			seen[p.Type] = true
+			types = append(types, p.Type)
+		}
+	}
+	return types
+}
+
+// singlePluginList returns the same list as libcni.ConfListFromConf for a
+// single plugin configuration, without converting numbers to floating point
+func singlePluginList(rawConfig map[string]interface{}) map[string]interface{} {
+	list := map[string]interface{}{
+		"plugins": []interface{}{rawConfig},
+	}
+	if name, ok := rawConfig["name"]; ok {
+		list["name"] = name
+	}
+	if cniVersion, ok := rawConfig["cniVersion"]; ok {
+		list["cniVersion"] = cniVersion
+	}
+	return list
+}
+
+// observeDeviceInfoOperation logs and records a device info file operation
+func observeDeviceInfoOperation(ctx context.Context, operation, path string, err error) {
+	recorderFrom(ctx).IncDeviceInfoOperation(operation, err)
+	log := loggerFrom(ctx)
+	if err != nil {
+		log.V(logLevelDebug).Info("Device info operation failed", "operation", operation, "path", path, "err", err)
+		return
+	}
+	log.V(logLevelDebug).Info("Device info operation", "operation", operation, "path", path)
+}
+
 // loadDeviceInfo loads a Device Information file
-func loadDeviceInfo(path string) (*v1.DeviceInfo, error) {
+func loadDeviceInfo(ctx context.Context, path string) (devInfo *v1.DeviceInfo, err error) {
+	defer func() { observeDeviceInfoOperation(ctx, deviceInfoLoad, path, err) }()
+	return readDeviceInfo(path)
+}
+
+func readDeviceInfo(path string) (*v1.DeviceInfo, error) {
 	var devInfo v1.DeviceInfo
 
 	bytes, err := ioutil.ReadFile(path)
@@ -145,7 +297,8 @@ func loadDeviceInfo(path string) (*v1.DeviceInfo, error) {
 }
 
 // cleanDeviceInfo removes a Device Information file
-func cleanDeviceInfo(path string) error {
+func cleanDeviceInfo(ctx context.Context, path string) (err error) {
+	defer func() { observeDeviceInfoOperation(ctx, deviceInfoClean, path, err) }()
 	if _, err := os.Stat(path); !os.IsNotExist(err) {
 		return os.Remove(path)
 	}
@@ -153,7 +306,8 @@ func cleanDeviceInfo(path string) error {
 }
 
 // saveDeviceInfo writes a Device Information file
-func saveDeviceInfo(devInfo *v1.DeviceInfo, path string) error {
+func saveDeviceInfo(ctx context.Context, devInfo *v1.DeviceInfo, path string) (err error) {
+	defer func() { observeDeviceInfoOperation(ctx, deviceInfoSave, path, err) }()
 	if devInfo == nil {
 		return fmt.Errorf("Device Information is null")
 	}
@@ -198,40 +352,83 @@ func GetCNIDeviceInfoPath(filename string) string {
 // LoadDeviceInfoFromDP loads a DeviceInfo structure from file created by a Device Plugin
 // Returns an error if the device information is malformed and (nil, nil) if it does not exist
 func LoadDeviceInfoFromDP(resourceName string, deviceID string) (*v1.DeviceInfo, error) {
-	return loadDeviceInfo(getDPDeviceInfoPath(resourceName, deviceID))
+	return LoadDeviceInfoFromDPWithContext(context.Background(), resourceName, deviceID)
+}
+
+// LoadDeviceInfoFromDPWithContext is LoadDeviceInfoFromDP, logging and
+// recording the operation to the logger and metrics recorder of ctx
+func LoadDeviceInfoFromDPWithContext(ctx context.Context, resourceName string, deviceID string) (*v1.DeviceInfo, error) {
+	return loadDeviceInfo(ctx, getDPDeviceInfoPath(resourceName, deviceID))
 }
 
 // SaveDeviceInfoForDP saves a DeviceInfo structure created by a Device Plugin
 func SaveDeviceInfoForDP(resourceName string, deviceID string, devInfo *v1.DeviceInfo) error {
-	return saveDeviceInfo(devInfo, getDPDeviceInfoPath(resourceName, deviceID))
+	return SaveDeviceInfoForDPWithContext(context.Background(), resourceName, deviceID, devInfo)
+}
+
+// SaveDeviceInfoForDPWithContext is SaveDeviceInfoForDP, logging and
+// recording the operation to the logger and metrics recorder of ctx
+func SaveDeviceInfoForDPWithContext(ctx context.Context, resourceName string, deviceID string, devInfo *v1.DeviceInfo) error {
+	return saveDeviceInfo(ctx, devInfo, getDPDeviceInfoPath(resourceName, deviceID))
 }
 
 // CleanDeviceInfoForDP removes a DeviceInfo DP File.
 func CleanDeviceInfoForDP(resourceName string, deviceID string) error {
-	return cleanDeviceInfo(getDPDeviceInfoPath(resourceName, deviceID))
+	return CleanDeviceInfoForDPWithContext(context.Background(), resourceName, deviceID)
+}
+
+// CleanDeviceInfoForDPWithContext is CleanDeviceInfoForDP, logging and
+// recording the operation to the logger and metrics recorder of ctx
+func CleanDeviceInfoForDPWithContext(ctx context.Context, resourceName string, deviceID string) error {
+	return cleanDeviceInfo(ctx, getDPDeviceInfoPath(resourceName, deviceID))
 }
 
 // LoadDeviceInfoFromCNI loads a DeviceInfo structure from created by a CNI.
 // Returns an error if the device information is malformed and (nil, nil) if it does not exist
 func LoadDeviceInfoFromCNI(cniPath string) (*v1.DeviceInfo, error) {
-	return loadDeviceInfo(cniPath)
+	return LoadDeviceInfoFromCNIWithContext(context.Background(), cniPath)
+}
+
+// LoadDeviceInfoFromCNIWithContext is LoadDeviceInfoFromCNI, logging and
+// recording the operation to the logger and metrics recorder of ctx
+func LoadDeviceInfoFromCNIWithContext(ctx context.Context, cniPath string) (*v1.DeviceInfo, error) {
+	return loadDeviceInfo(ctx, cniPath)
 }
 
 // SaveDeviceInfoForCNI saves a DeviceInfo structure created by a CNI
 func SaveDeviceInfoForCNI(cniPath string, devInfo *v1.DeviceInfo) error {
-	return saveDeviceInfo(devInfo, cniPath)
+	return SaveDeviceInfoForCNIWithContext(context.Background(), cniPath, devInfo)
+}
+
+// SaveDeviceInfoForCNIWithContext is SaveDeviceInfoForCNI, logging and
+// recording the operation to the logger and metrics recorder of ctx
+func SaveDeviceInfoForCNIWithContext(ctx context.Context, cniPath string, devInfo *v1.DeviceInfo) error {
+	return saveDeviceInfo(ctx, devInfo, cniPath)
 }
 
 // CopyDeviceInfoForCNIFromDP saves a DeviceInfo structure created by a DP to a CNI File.
 func CopyDeviceInfoForCNIFromDP(cniPath string, resourceName string, deviceID string) error {
-	devInfo, err := loadDeviceInfo(getDPDeviceInfoPath(resourceName, deviceID))
+	return CopyDeviceInfoForCNIFromDPWithContext(context.Background(), cniPath, resourceName, deviceID)
+}
+
+// CopyDeviceInfoForCNIFromDPWithContext is CopyDeviceInfoForCNIFromDP,
+// logging and recording the operations to the logger and metrics recorder of
+// ctx
+func CopyDeviceInfoForCNIFromDPWithContext(ctx context.Context, cniPath string, resourceName string, deviceID string) error {
+	devInfo, err := loadDeviceInfo(ctx, getDPDeviceInfoPath(resourceName, deviceID))
 	if err != nil {
 		return err
 	}
-	return saveDeviceInfo(devInfo, cniPath)
+	return saveDeviceInfo(ctx, devInfo, cniPath)
 }
 
 // CleanDeviceInfoForCNI removes a DeviceInfo CNI File.
 func CleanDeviceInfoForCNI(cniPath string) error {
-	return cleanDeviceInfo(cniPath)
+	return CleanDeviceInfoForCNIWithContext(context.Background(), cniPath)
+}
+
+// CleanDeviceInfoForCNIWithContext is CleanDeviceInfoForCNI, logging and
+// recording the operation to the logger and metrics recorder of ctx
+func CleanDeviceInfoForCNIWithContext(ctx context.Context, cniPath string) error {
+	return cleanDeviceInfo(ctx, cniPath)
 }
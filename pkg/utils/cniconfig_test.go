@@ -151,6 +151,88 @@ var _ = Describe("CNI config manipulations", func() {
 			_, err := GetCNIConfig(&netattachdef, tmpDir)
 			Expect(err).To(HaveOccurred())
 		})
+
+	})
+
+	Context("CNI network config list", func() {
+		It("converts a single plugin config into a list and injects the name", func() {
+			netattachdef := v1.NetworkAttachmentDefinition{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-net-attach-def",
+					Namespace: "testnamespace",
+				},
+				Spec: v1.NetworkAttachmentDefinitionSpec{
+					Config: `{"cniVersion": "0.3.1", "type": "macvlan", "master": "eth0"}`,
+				},
+			}
+			confList, err := GetCNINetworkConfigList(&netattachdef, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(confList.Name).To(Equal("test-net-attach-def"))
+			Expect(confList.CNIVersion).To(Equal("0.3.1"))
+			Expect(confList.Plugins).To(HaveLen(1))
+			Expect(confList.Plugins[0].Network.Type).To(Equal("macvlan"))
+		})
+
+		It("loads a conflist file and overrides the cniVersion", func() {
+			tmpConfFilePath := filepath.Join(tmpDir, "testCNI.conflist")
+			cniConfig := `{
+			"name": "test-net-attach-def",
+			"cniVersion": "0.3.1",
+			"plugins": [{"type": "bridge"}, {"type": "tuning"}]
+		}`
+			ioutil.WriteFile(tmpConfFilePath, []byte(cniConfig), 0644)
+
+			netattachdef := v1.NetworkAttachmentDefinition{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-net-attach-def",
+					Namespace: "testnamespace",
+				},
+			}
+			confList, err := GetCNINetworkConfigListWithVersion(&netattachdef, tmpDir, "1.0.0")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(confList.Name).To(Equal("test-net-attach-def"))
+			Expect(confList.CNIVersion).To(Equal("1.0.0"))
+			Expect(confList.Plugins).To(HaveLen(2))
+		})
+
+		It("keeps large integers when overriding the cniVersion", func() {
+			netattachdef := v1.NetworkAttachmentDefinition{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-net-attach-def",
+					Namespace: "testnamespace",
+				},
+				Spec: v1.NetworkAttachmentDefinitionSpec{
+					Config: `{"cniVersion": "0.3.1", "type": "bridge", "vlan": 9007199254740993}`,
+				},
+			}
+			confList, err := GetCNINetworkConfigListWithVersion(&netattachdef, "", "1.0.0")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(confList.CNIVersion).To(Equal("1.0.0"))
+			Expect(string(confList.Bytes)).To(ContainSubstring(`"vlan":9007199254740993`))
+		})
+
+		It("fails on a config without a plugin type", func() {
+			netattachdef := v1.NetworkAttachmentDefinition{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-net-attach-def",
+					Namespace: "testnamespace",
+				},
+				Spec: v1.NetworkAttachmentDefinitionSpec{
+					Config: `{"cniVersion": "0.3.1"}`,
+				},
+			}
+			_, err := GetCNINetworkConfigList(&netattachdef, "")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("Plugin types", func() {
+		It("returns the distinct plugin types of a config list", func() {
+			Expect(PluginTypes(`{"cniVersion": "1.0.0", "type": "bridge"}`)).To(Equal([]string{"bridge"}))
+			Expect(PluginTypes(`{"cniVersion": "1.0.0", "plugins": [{"type": "macvlan"}, {"type": "tuning"}, {}, {"type": "tuning"}]}`)).To(Equal([]string{"macvlan", "tuning"}))
+			Expect(PluginTypes("")).To(BeNil())
+			Expect(PluginTypes("{")).To(BeNil())
+		})
 	})
 
 })
@@ -0,0 +1,209 @@
+// Copyright (c) 2024 Kubernetes Network Plumbing Working Group
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/containernetworking/cni/pkg/version"
+
+	v1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+)
+
+// CNIConfigInfo holds the top level, version related settings of a CNI
+// config or config list
+type CNIConfigInfo struct {
+	Name string
+	// CNIVersion is the version the runtime should use to execute the config
+	CNIVersion string
+	// CNIVersions lists every version the config declares support for
+	CNIVersions []string
+	// IsList is true when the config is a config list
+	IsList                 bool
+	DisableCheck           bool
+	DisableGC              bool
+	LoadOnlyInlinedPlugins bool
+}
+
+// CheckEnabled reports whether the runtime should issue CHECK for the config
+func (i *CNIConfigInfo) CheckEnabled() bool {
+	gte, _ := version.GreaterThanOrEqualTo(i.CNIVersion, "0.4.0")
+	return gte && !i.DisableCheck
+}
+
+// GCEnabled reports whether the runtime should issue GC for the config
+func (i *CNIConfigInfo) GCEnabled() bool {
+	gte, _ := version.GreaterThanOrEqualTo(i.CNIVersion, "1.1.0")
+	return gte && !i.DisableGC
+}
+
+// GetCNIConfigInfo parses the version related settings of the
+// NetworkAttachmentDefinition's CNI configuration, see ParseCNIConfigInfo
+func GetCNIConfigInfo(net *v1.NetworkAttachmentDefinition, confDir string, supportedVersions []string) (*CNIConfigInfo, error) {
+	config, err := GetCNIConfig(net, confDir)
+	if err != nil {
+		return nil, err
+	}
+	return ParseCNIConfigInfo(config, supportedVersions)
+}
+
+// ParseCNIConfigInfo parses and validates the version related settings of a
+// CNI config or config list. The effective CNIVersion is the highest version
+// of "cniVersions" that is also in supportedVersions; like CNI 1.1 runtimes,
+// "cniVersion" is only used when "cniVersions" lists no version. When
+// supportedVersions is empty, the versions supported by the vendored CNI
+// library are used.
+func ParseCNIConfigInfo(config []byte, supportedVersions []string) (*CNIConfigInfo, error) {
+	var rawConfig map[string]interface{}
+	if err := json.Unmarshal(config, &rawConfig); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal CNI config: %v", err)
+	}
+
+	info := &CNIConfigInfo{}
+	var err error
+
+	if name, ok := rawConfig["name"]; ok {
+		if info.Name, ok = name.(string); !ok {
+			return nil, fmt.Errorf("invalid name type %T", name)
+		}
+	}
+
+	var declared []string
+	if rawVersion, ok := rawConfig["cniVersion"]; ok {
+		cniVersion, ok := rawVersion.(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid cniVersion type %T", rawVersion)
+		}
+		if _, _, _, err := version.ParseVersion(cniVersion); err != nil {
+			return nil, fmt.Errorf("invalid cniVersion: %v", err)
+		}
+		declared = []string{cniVersion}
+	}
+	if rawVersions, ok := rawConfig["cniVersions"]; ok {
+		items, ok := rawVersions.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid cniVersions type %T", rawVersions)
+		}
+		for i, item := range items {
+			cniVersion, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("invalid type for cniVersions index %d: %T", i, item)
+			}
+			if _, _, _, err := version.ParseVersion(cniVersion); err != nil {
+				return nil, fmt.Errorf("invalid cniVersions entry at index %d: %v", i, err)
+			}
+			info.CNIVersions = append(info.CNIVersions, cniVersion)
+		}
+		if len(info.CNIVersions) > 0 {
+			declared = info.CNIVersions
+		}
+	}
+
+	if len(supportedVersions) == 0 {
+		supportedVersions = version.All.SupportedVersions()
+	}
+	if len(declared) > 0 {
+		info.CNIVersion, err = highestMutualVersion(declared, supportedVersions)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if _, info.IsList = rawConfig["plugins"]; info.IsList {
+		plugins, ok := rawConfig["plugins"].([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid plugins type %T", rawConfig["plugins"])
+		}
+		if info.LoadOnlyInlinedPlugins, err = parseBoolField(rawConfig, "loadOnlyInlinedPlugins"); err != nil {
+			return nil, err
+		}
+		if len(plugins) == 0 && info.LoadOnlyInlinedPlugins {
+			return nil, fmt.Errorf("no plugins in list and loadOnlyInlinedPlugins is set")
+		}
+	}
+	if info.DisableCheck, err = parseBoolField(rawConfig, "disableCheck"); err != nil {
+		return nil, err
+	}
+	if info.DisableGC, err = parseBoolField(rawConfig, "disableGC"); err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
+
+// checkCNIConfigInfo validates the version related settings of a config
+// returned by the config helpers, against the versions supported by the
+// vendored CNI library
+func checkCNIConfigInfo(config []byte) error {
+	_, err := ParseCNIConfigInfo(config, nil)
+	return err
+}
+
+// parseBoolField parses an optional boolean field, accepting the "true" and
+// "false" strings like libcni does for disableCheck
+func parseBoolField(rawConfig map[string]interface{}, field string) (bool, error) {
+	raw, ok := rawConfig[field]
+	if !ok {
+		return false, nil
+	}
+	switch value := raw.(type) {
+	case bool:
+		return value, nil
+	case string:
+		switch strings.ToLower(value) {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		}
+		return false, fmt.Errorf("invalid %s value %q", field, value)
+	}
+	return false, fmt.Errorf("invalid %s type %T", field, raw)
+}
+
+// highestMutualVersion returns the highest of the declared versions that is
+// also supported
+func highestMutualVersion(declared, supported []string) (string, error) {
+	highest := ""
+	for _, d := range declared {
+		for _, s := range supported {
+			if !sameVersion(d, s) {
+				continue
+			}
+			if gte, _ := version.GreaterThanOrEqualTo(d, highest); highest == "" || gte {
+				highest = d
+			}
+		}
+	}
+	if highest == "" {
+		return "", fmt.Errorf("none of the CNI versions %s is supported (supported: %s)",
+			strings.Join(declared, ", "), strings.Join(supported, ", "))
+	}
+	return highest, nil
+}
+
+func sameVersion(a, b string) bool {
+	aMajor, aMinor, aMicro, err := version.ParseVersion(a)
+	if err != nil {
+		return false
+	}
+	bMajor, bMinor, bMicro, err := version.ParseVersion(b)
+	if err != nil {
+		return false
+	}
+	return aMajor == bMajor && aMinor == bMinor && aMicro == bMicro
+}
@@ -0,0 +1,128 @@
+// Copyright (c) 2024 Kubernetes Network Plumbing Working Group
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CNI config info", func() {
+	It("picks the highest mutually supported version from cniVersions", func() {
+		info, err := ParseCNIConfigInfo([]byte(`{
+			"name": "test",
+			"cniVersion": "0.4.0",
+			"cniVersions": ["1.0.0", "1.1.0", "2.0.0"],
+			"disableGC": true,
+			"loadOnlyInlinedPlugins": true,
+			"plugins": [{"type": "bridge"}]
+		}`), nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info.Name).To(Equal("test"))
+		Expect(info.IsList).To(BeTrue())
+		Expect(info.CNIVersion).To(Equal("1.1.0"))
+		Expect(info.CNIVersions).To(Equal([]string{"1.0.0", "1.1.0", "2.0.0"}))
+		Expect(info.DisableGC).To(BeTrue())
+		Expect(info.LoadOnlyInlinedPlugins).To(BeTrue())
+		Expect(info.CheckEnabled()).To(BeTrue())
+		Expect(info.GCEnabled()).To(BeFalse())
+	})
+
+	It("honours the runtime's supported versions", func() {
+		info, err := ParseCNIConfigInfo([]byte(`{
+			"name": "test",
+			"cniVersions": ["0.3.1", "1.0.0", "1.1.0"],
+			"disableCheck": "true",
+			"plugins": [{"type": "bridge"}]
+		}`), []string{"0.3.1", "1.0.0"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info.CNIVersion).To(Equal("1.0.0"))
+		Expect(info.DisableCheck).To(BeTrue())
+		Expect(info.CheckEnabled()).To(BeFalse())
+		Expect(info.GCEnabled()).To(BeFalse())
+	})
+
+	It("prefers cniVersions over cniVersion", func() {
+		info, err := ParseCNIConfigInfo([]byte(`{
+			"name": "test",
+			"cniVersion": "1.1.0",
+			"cniVersions": ["0.4.0", "1.0.0"],
+			"plugins": [{"type": "bridge"}]
+		}`), nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info.CNIVersion).To(Equal("1.0.0"))
+
+		_, err = ParseCNIConfigInfo([]byte(`{
+			"name": "test",
+			"cniVersion": "1.0.0",
+			"cniVersions": ["1.1.0"],
+			"plugins": [{"type": "bridge"}]
+		}`), []string{"1.0.0"})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("enables GC for CNI 1.1 configs", func() {
+		info, err := ParseCNIConfigInfo([]byte(`{"name": "test", "cniVersion": "1.1.0", "type": "bridge"}`), nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info.IsList).To(BeFalse())
+		Expect(info.GCEnabled()).To(BeTrue())
+	})
+
+	It("rejects configs without a mutually supported version", func() {
+		_, err := ParseCNIConfigInfo([]byte(`{"name": "test", "cniVersions": ["1.1.0"], "plugins": [{"type": "bridge"}]}`), []string{"1.0.0"})
+		Expect(err).To(MatchError(ContainSubstring("none of the CNI versions")))
+	})
+
+	It("rejects invalid values", func() {
+		_, err := ParseCNIConfigInfo([]byte(`{"name": "test", "cniVersions": ["a.b"], "plugins": [{"type": "bridge"}]}`), nil)
+		Expect(err).To(HaveOccurred())
+		_, err = ParseCNIConfigInfo([]byte(`{"name": "test", "cniVersion": "1.1.0", "disableGC": "maybe", "plugins": [{"type": "bridge"}]}`), nil)
+		Expect(err).To(HaveOccurred())
+		_, err = ParseCNIConfigInfo([]byte(`{"name": "test", "cniVersion": "1.1.0", "loadOnlyInlinedPlugins": true, "plugins": []}`), nil)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("is validated by the config helpers", func() {
+		_, err := GetCNIConfigFromSpec(`{"cniVersions": ["1.1.0"], "disableGC": "maybe", "plugins": [{"type": "bridge"}]}`, "net1")
+		var configErr *InvalidConfigError
+		Expect(errors.As(err, &configErr)).To(BeTrue())
+		Expect(err).To(MatchError(ContainSubstring("invalid disableGC value")))
+
+		tmpDir, err := os.MkdirTemp("", "multus-tmp")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(tmpDir)
+		path := filepath.Join(tmpDir, "10-net1.conflist")
+		Expect(os.WriteFile(path, []byte(`{"name": "net1", "cniVersions": ["9.0.0"], "plugins": [{"type": "bridge"}]}`), 0644)).To(Succeed())
+		_, err = GetCNIConfigFromFile("net1", tmpDir)
+		Expect(errors.As(err, &configErr)).To(BeTrue())
+		Expect(configErr.Path).To(Equal(path))
+		Expect(err).To(MatchError(ContainSubstring("none of the CNI versions")))
+
+		net := &v1.NetworkAttachmentDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "net1", Namespace: "default"},
+			Spec:       v1.NetworkAttachmentDefinitionSpec{Config: `{"cniVersion": "1.0.0", "type": "bridge"}`},
+		}
+		_, err = GetCNINetworkConfigListWithVersion(net, "", "9.0.0")
+		Expect(err).To(MatchError(ContainSubstring("none of the CNI versions")))
+	})
+})
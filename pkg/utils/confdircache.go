@@ -0,0 +1,220 @@
+// Copyright (c) 2024 Kubernetes Network Plumbing Working Group
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/containernetworking/cni/libcni"
+	"github.com/fsnotify/fsnotify"
+
+	v1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+)
+
+// DefaultConfDirCacheDebounce is how long ConfDirCache waits for changes
+// in the configuration directory to settle before reloading it
+const DefaultConfDirCacheDebounce = 200 * time.Millisecond
+
+var confDirExtensions = []string{".conf", ".json", ".conflist"}
+
+// ConfDirCache indexes the CNI configurations of a directory by network
+// name. It offers the same lookups as GetCNIConfigFromFile without reading
+// the directory on every call and, once started, reloads the index when
+// files in the directory change.
+type ConfDirCache struct {
+	confDir  string
+	debounce time.Duration
+
+	mu sync.RWMutex
+	// files holds the config files in lexical order
+	files []string
+	// configs maps network names to the files defining them
+	configs map[string][]string
+	// bytes holds the raw configuration of every valid file
+	bytes map[string][]byte
+	// errs holds the load error of every invalid file
+	errs map[string]error
+}
+
+// NewConfDirCache creates a ConfDirCache for confDir and loads its initial
+// content. A zero debounce uses DefaultConfDirCacheDebounce.
+func NewConfDirCache(confDir string, debounce time.Duration) (*ConfDirCache, error) {
+	if debounce == 0 {
+		debounce = DefaultConfDirCacheDebounce
+	}
+	c := &ConfDirCache{
+		confDir:  confDir,
+		debounce: debounce,
+	}
+	if err := c.Refresh(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Refresh reloads every configuration file of the directory
+func (c *ConfDirCache) Refresh() error {
+	files, err := libcni.ConfFiles(c.confDir, confDirExtensions)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to list CNI configs in %s: %v", c.confDir, err)
+	}
+
+	configs := make(map[string][]string)
+	bytes := make(map[string][]byte)
+	errs := make(map[string]error)
+	for _, confFile := range files {
+		netName, configBytes, err := loadCNIConfigFile(confFile)
+		if err != nil {
+			logger().V(logLevelDebug).Info("Skipping invalid CNI config file", "file", confFile, "err", err)
+			errs[confFile] = err
+			continue
+		}
+		configs[netName] = append(configs[netName], confFile)
+		bytes[confFile] = configBytes
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.files = files
+	c.configs = configs
+	c.bytes = bytes
+	c.errs = errs
+	return nil
+}
+
+// Start watches the configuration directory and refreshes the cache when
+// files change, until ctx is done. The directory must exist.
+func (c *ConfDirCache) Start(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create watcher: %v", err)
+	}
+	if err := watcher.Add(c.confDir); err != nil {
+		watcher.Close()
+		return fmt.Errorf("failed to watch %s: %v", c.confDir, err)
+	}
+	// Catch up with changes made before the watch was established
+	if err := c.Refresh(); err != nil {
+		watcher.Close()
+		return err
+	}
+
+	go c.watch(ctx, watcher)
+	return nil
+}
+
+func (c *ConfDirCache) watch(ctx context.Context, watcher *fsnotify.Watcher) {
+	defer watcher.Close()
+	log := loggerFrom(ctx)
+
+	timer := time.NewTimer(c.debounce)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if !isConfFile(event.Name) {
+				continue
+			}
+			timer.Reset(c.debounce)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			log.Error(err, "Watch of the CNI config directory failed", "dir", c.confDir)
+			// Events may have been dropped, reload to stay consistent
+			timer.Reset(c.debounce)
+		case <-timer.C:
+			// A failed reload keeps serving the previous content
+			if err := c.Refresh(); err != nil {
+				log.Error(err, "Failed to reload the CNI config directory", "dir", c.confDir)
+			}
+		}
+	}
+}
+
+func isConfFile(path string) bool {
+	ext := filepath.Ext(path)
+	for _, e := range confDirExtensions {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
+
+// GetCNIConfigFromFile returns the configuration whose network name matches
+// name, or the first configuration in lexical order when name is empty. It
+// returns the same errors as the GetCNIConfigFromFile function: an
+// *InvalidConfigError for a malformed file found before the network and a
+// *NetworkNotFoundError when no file defines it. It is also an
+// *InvalidConfigError, for the second file, when more than one file defines
+// the network.
+func (c *ConfDirCache) GetCNIConfigFromFile(name string) ([]byte, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if len(c.files) == 0 {
+		return nil, &NetworkNotFoundError{Name: name, ConfDir: c.confDir, NoNetworks: true}
+	}
+
+	for _, confFile := range c.files {
+		if fileErr, ok := c.errs[confFile]; ok {
+			return nil, fileErr
+		}
+		if name == "" {
+			return c.bytes[confFile], nil
+		}
+		if files := c.configs[name]; len(files) > 0 && files[0] == confFile {
+			if len(files) > 1 {
+				return nil, &InvalidConfigError{Path: files[1],
+					Err: fmt.Errorf("network %s is also defined by %s", name, confFile)}
+			}
+			return c.bytes[confFile], nil
+		}
+	}
+	return nil, &NetworkNotFoundError{Name: name, ConfDir: c.confDir}
+}
+
+// GetCNIConfig returns the NetworkAttachmentDefinition's CNI configuration
+// like the GetCNIConfig function, looking configurations up in the cache
+// when the spec is empty
+func (c *ConfDirCache) GetCNIConfig(net *v1.NetworkAttachmentDefinition) ([]byte, error) {
+	emptySpec := v1.NetworkAttachmentDefinitionSpec{}
+	if net.Spec == emptySpec {
+		config, err := c.GetCNIConfigFromFile(net.Name)
+		if err != nil {
+			return nil, fmt.Errorf("GetCNIConfig: err in GetCNIConfigFromFile: %w", err)
+		}
+		return config, nil
+	}
+
+	config, err := GetCNIConfigFromSpec(net.Spec.Config, net.Name)
+	if err != nil {
+		return nil, fmt.Errorf("GetCNIConfig: err in getCNIConfigFromSpec: %w", err)
+	}
+	return config, nil
+}
@@ -0,0 +1,133 @@
+// Copyright (c) 2024 Kubernetes Network Plumbing Working Group
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	v1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CNI config directory cache", func() {
+	var tmpDir string
+
+	writeConf := func(filename, config string) {
+		err := ioutil.WriteFile(filepath.Join(tmpDir, filename), []byte(config), 0644)
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "multus-tmp")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		err := os.RemoveAll(tmpDir)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("looks configurations up by network name", func() {
+		writeConf("10-first.conf", `{"cniVersion": "0.3.1", "name": "first", "type": "bridge"}`)
+		writeConf("20-second.conflist", `{"cniVersion": "0.3.1", "name": "second", "plugins": [{"type": "bridge"}]}`)
+
+		cache, err := NewConfDirCache(tmpDir, 0)
+		Expect(err).NotTo(HaveOccurred())
+
+		config, err := cache.GetCNIConfigFromFile("second")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config).To(MatchJSON(`{"cniVersion": "0.3.1", "name": "second", "plugins": [{"type": "bridge"}]}`))
+
+		config, err = cache.GetCNIConfigFromFile("")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config).To(MatchJSON(`{"cniVersion": "0.3.1", "name": "first", "type": "bridge"}`))
+
+		config, err = cache.GetCNIConfig(&v1.NetworkAttachmentDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "first", Namespace: "testnamespace"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config).To(MatchJSON(`{"cniVersion": "0.3.1", "name": "first", "type": "bridge"}`))
+	})
+
+	It("reports networks defined by multiple files", func() {
+		writeConf("10-first.conf", `{"cniVersion": "0.3.1", "name": "dup", "type": "bridge"}`)
+		writeConf("20-second.conf", `{"cniVersion": "0.3.1", "name": "dup", "type": "macvlan"}`)
+
+		cache, err := NewConfDirCache(tmpDir, 0)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = cache.GetCNIConfigFromFile("dup")
+		var configErr *InvalidConfigError
+		Expect(errors.As(err, &configErr)).To(BeTrue())
+		Expect(configErr.Path).To(Equal(filepath.Join(tmpDir, "20-second.conf")))
+		Expect(err).To(MatchError(ContainSubstring("also defined by " + filepath.Join(tmpDir, "10-first.conf"))))
+	})
+
+	It("returns the errors of GetCNIConfigFromFile", func() {
+		writeConf("20-net1.conf", `{"cniVersion": "0.3.1", "name": "net1", "type": "bridge"}`)
+
+		cache, err := NewConfDirCache(tmpDir, 0)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = cache.GetCNIConfigFromFile("missing")
+		var notFoundErr *NetworkNotFoundError
+		Expect(errors.As(err, &notFoundErr)).To(BeTrue())
+		_, fileErr := GetCNIConfigFromFile("missing", tmpDir)
+		Expect(err).To(MatchError(fileErr.Error()))
+
+		writeConf("10-broken.conf", `***invalid json file***`)
+		Expect(cache.Refresh()).To(Succeed())
+		for _, name := range []string{"net1", "missing", ""} {
+			_, err = cache.GetCNIConfigFromFile(name)
+			var configErr *InvalidConfigError
+			Expect(errors.As(err, &configErr)).To(BeTrue())
+			Expect(configErr.Path).To(Equal(filepath.Join(tmpDir, "10-broken.conf")))
+			_, fileErr = GetCNIConfigFromFile(name, tmpDir)
+			Expect(err).To(MatchError(fileErr.Error()))
+		}
+	})
+
+	It("refreshes when the directory changes", func() {
+		cache, err := NewConfDirCache(tmpDir, 10*time.Millisecond)
+		Expect(err).NotTo(HaveOccurred())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		Expect(cache.Start(ctx)).To(Succeed())
+
+		_, err = cache.GetCNIConfigFromFile("late")
+		Expect(err).To(HaveOccurred())
+
+		writeConf("10-late.conf", `{"cniVersion": "0.3.1", "name": "late", "type": "bridge"}`)
+		Eventually(func() error {
+			_, err := cache.GetCNIConfigFromFile("late")
+			return err
+		}, 5*time.Second, 10*time.Millisecond).Should(Succeed())
+
+		Expect(os.Remove(filepath.Join(tmpDir, "10-late.conf"))).To(Succeed())
+		Eventually(func() error {
+			_, err := cache.GetCNIConfigFromFile("late")
+			return err
+		}, 5*time.Second, 10*time.Millisecond).Should(HaveOccurred())
+	})
+})
@@ -0,0 +1,166 @@
+// Copyright (c) 2024 Kubernetes Network Plumbing Working Group
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/containernetworking/cni/libcni"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+
+	v1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+	"github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/client/clientset/versioned/scheme"
+)
+
+// ConfigFileToNAD loads a CNI .conf, .json or .conflist file and returns a
+// NetworkAttachmentDefinition in namespace with the file's configuration,
+// named after its network
+func ConfigFileToNAD(confFile, namespace string) (*v1.NetworkAttachmentDefinition, error) {
+	netName, config, err := loadCNIConfigFile(confFile)
+	if err != nil {
+		return nil, err
+	}
+	if errs := validation.IsDNS1123Subdomain(netName); len(errs) > 0 {
+		return nil, fmt.Errorf("network name %q of %s is not a valid object name: %s", netName, confFile, strings.Join(errs, ", "))
+	}
+
+	return &v1.NetworkAttachmentDefinition{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1.SchemeGroupVersion.String(),
+			Kind:       "NetworkAttachmentDefinition",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      netName,
+			Namespace: namespace,
+		},
+		Spec: v1.NetworkAttachmentDefinitionSpec{
+			Config: string(bytes.TrimSpace(config)),
+		},
+	}, nil
+}
+
+// NADToConfigFile writes the NetworkAttachmentDefinition's configuration to
+// confDir as <name>.conflist and returns its path. Single plugin
+// configurations are converted to a list, and the network name is set to the
+// object's name so GetCNIConfig finds the file when the object's spec is
+// empty. The file is written atomically. As the file name has no namespace,
+// it is an error for the file to exist with a different config, such as the
+// one of a network of the same name in another namespace.
+func NADToConfigFile(net *v1.NetworkAttachmentDefinition, confDir string) (string, error) {
+	if net.Spec.Config == "" {
+		return "", fmt.Errorf("network %s/%s has no config", net.Namespace, net.Name)
+	}
+
+	rawConfig, err := decodeConfig([]byte(net.Spec.Config))
+	if err != nil {
+		return "", fmt.Errorf("failed to unmarshal Spec.Config: %v", err)
+	}
+	rawConfig["name"] = net.Name
+	if _, ok := rawConfig["plugins"]; !ok {
+		if _, err := libcni.ConfFromBytes([]byte(net.Spec.Config)); err != nil {
+			return "", err
+		}
+		rawConfig = singlePluginList(rawConfig)
+	}
+	config, err := marshalCanonical(rawConfig)
+	if err != nil {
+		return "", fmt.Errorf("failed to re-marshal Spec.Config: %v", err)
+	}
+	if _, err := libcni.ConfListFromBytes(config); err != nil {
+		return "", err
+	}
+
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, config, "", "  "); err != nil {
+		return "", err
+	}
+	indented.WriteByte('\n')
+
+	path := filepath.Join(confDir, net.Name+".conflist")
+	existing, err := os.ReadFile(path)
+	switch {
+	case err == nil && !bytes.Equal(existing, indented.Bytes()):
+		return "", fmt.Errorf("%s already exists with a different config", path)
+	case err != nil && !os.IsNotExist(err):
+		return "", err
+	}
+	tmp, err := os.CreateTemp(confDir, "."+net.Name+"-*.tmp")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(indented.Bytes()); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// ReadNetworkAttachmentDefinitions decodes every NetworkAttachmentDefinition
+// of a YAML or JSON stream with one or more documents. Other versions of the
+// API are converted to v1.
+func ReadNetworkAttachmentDefinitions(r io.Reader) ([]*v1.NetworkAttachmentDefinition, error) {
+	var nads []*v1.NetworkAttachmentDefinition
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(r))
+	for {
+		doc, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+		obj, _, err := scheme.Codecs.UniversalDeserializer().Decode(doc, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := obj.(*v1.NetworkAttachmentDefinition); !ok {
+			converted, err := scheme.Scheme.ConvertToVersion(obj, v1.SchemeGroupVersion)
+			if err != nil {
+				return nil, fmt.Errorf("unsupported object %T: %v", obj, err)
+			}
+			obj = converted
+		}
+		nad, ok := obj.(*v1.NetworkAttachmentDefinition)
+		if !ok {
+			return nil, fmt.Errorf("unsupported object %T", obj)
+		}
+		nads = append(nads, nad)
+	}
+	return nads, nil
+}
@@ -0,0 +1,147 @@
+// Copyright (c) 2024 Kubernetes Network Plumbing Working Group
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CNI config file import and export", func() {
+	var tmpDir string
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = os.MkdirTemp("", "multus-tmp")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(tmpDir)).To(Succeed())
+	})
+
+	It("converts a conflist file to a network", func() {
+		confFile := filepath.Join(tmpDir, "10-bridge.conflist")
+		Expect(os.WriteFile(confFile, []byte(`{"cniVersion": "1.0.0", "name": "bridge-net", "plugins": [{"type": "bridge"}]}`+"\n"), 0644)).To(Succeed())
+
+		nad, err := ConfigFileToNAD(confFile, "infra")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(nad.Kind).To(Equal("NetworkAttachmentDefinition"))
+		Expect(nad.Name).To(Equal("bridge-net"))
+		Expect(nad.Namespace).To(Equal("infra"))
+		Expect(nad.Spec.Config).To(Equal(`{"cniVersion": "1.0.0", "name": "bridge-net", "plugins": [{"type": "bridge"}]}`))
+	})
+
+	It("fails when the network name is not a valid object name", func() {
+		confFile := filepath.Join(tmpDir, "10-bridge.conf")
+		Expect(os.WriteFile(confFile, []byte(`{"cniVersion": "1.0.0", "name": "Bridge_Net", "type": "bridge"}`), 0644)).To(Succeed())
+
+		_, err := ConfigFileToNAD(confFile, "infra")
+		Expect(err).To(MatchError(ContainSubstring(`network name "Bridge_Net"`)))
+	})
+
+	It("writes a network as a conflist found by the file fallback", func() {
+		nad := &v1.NetworkAttachmentDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "macvlan-net", Namespace: "infra"},
+			Spec: v1.NetworkAttachmentDefinitionSpec{
+				Config: `{"cniVersion": "0.4.0", "name": "other", "type": "macvlan", "master": "eth0"}`,
+			},
+		}
+		path, err := NADToConfigFile(nad, tmpDir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(path).To(Equal(filepath.Join(tmpDir, "macvlan-net.conflist")))
+
+		entries, err := os.ReadDir(tmpDir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(entries).To(HaveLen(1))
+
+		config, err := GetCNIConfig(&v1.NetworkAttachmentDefinition{ObjectMeta: nad.ObjectMeta}, tmpDir)
+		Expect(err).NotTo(HaveOccurred())
+		var confList map[string]interface{}
+		Expect(json.Unmarshal(config, &confList)).To(Succeed())
+		Expect(confList["name"]).To(Equal("macvlan-net"))
+		Expect(confList["cniVersion"]).To(Equal("0.4.0"))
+		Expect(confList["plugins"]).To(HaveLen(1))
+
+		// converting the file back gives the same network
+		back, err := ConfigFileToNAD(path, "infra")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(back.Name).To(Equal(nad.Name))
+	})
+
+	It("does not overwrite the network of another namespace", func() {
+		nad := &v1.NetworkAttachmentDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "bridge-net", Namespace: "infra"},
+			Spec:       v1.NetworkAttachmentDefinitionSpec{Config: `{"cniVersion": "1.0.0", "type": "bridge"}`},
+		}
+		path, err := NADToConfigFile(nad, tmpDir)
+		Expect(err).NotTo(HaveOccurred())
+		written, err := os.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+
+		// writing the same network again is a no-op
+		_, err = NADToConfigFile(nad, tmpDir)
+		Expect(err).NotTo(HaveOccurred())
+
+		other := nad.DeepCopy()
+		other.Namespace = "tenant"
+		other.Spec.Config = `{"cniVersion": "1.0.0", "type": "macvlan"}`
+		_, err = NADToConfigFile(other, tmpDir)
+		Expect(err).To(MatchError(path + " already exists with a different config"))
+		Expect(os.ReadFile(path)).To(Equal(written))
+	})
+
+	It("fails to write a network without config", func() {
+		nad := &v1.NetworkAttachmentDefinition{ObjectMeta: metav1.ObjectMeta{Name: "empty", Namespace: "infra"}}
+		_, err := NADToConfigFile(nad, tmpDir)
+		Expect(err).To(MatchError("network infra/empty has no config"))
+	})
+
+	It("reads networks of every version from a manifest", func() {
+		nads, err := ReadNetworkAttachmentDefinitions(strings.NewReader(`apiVersion: k8s.cni.cncf.io/v1
+kind: NetworkAttachmentDefinition
+metadata:
+  name: bridge-net
+spec:
+  config: '{"cniVersion": "1.0.0", "type": "bridge"}'
+---
+---
+apiVersion: k8s.cni.cncf.io/v2alpha1
+kind: NetworkAttachmentDefinition
+metadata:
+  name: macvlan-net
+spec:
+  cniVersion: 1.0.0
+  plugins:
+  - type: macvlan
+`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(nads).To(HaveLen(2))
+		Expect(nads[1].Name).To(Equal("macvlan-net"))
+		Expect(nads[1].Spec.Config).To(ContainSubstring(`"type":"macvlan"`))
+
+		_, err = ReadNetworkAttachmentDefinitions(strings.NewReader(`{"apiVersion": "k8s.cni.cncf.io/v1", "kind": "ClusterNetworkAttachmentDefinition", "metadata": {"name": "shared"}}`))
+		Expect(err).To(MatchError(ContainSubstring("unsupported object")))
+	})
+})
@@ -0,0 +1,250 @@
+// Copyright (c) 2024 Kubernetes Network Plumbing Working Group
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+
+	v1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+)
+
+// ConfigHash returns the hash of the NetworkAttachmentDefinition's config
+// as "sha256:<hex>", computed on its canonical form with the network name
+// injected, so it does not change with formatting. It is empty for networks
+// without config, whose config is read from the node.
+func ConfigHash(net *v1.NetworkAttachmentDefinition) (string, error) {
+	if net.Spec.Config == "" {
+		return "", nil
+	}
+	config, err := GetCNIConfigFromSpec(net.Spec.Config, net.Name)
+	if err != nil {
+		return "", err
+	}
+	canonical, err := CanonicalizeConfig(config)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(canonical)
+	return "sha256:" + hex.EncodeToString(sum[:]), nil
+}
+
+// NetworkConfigHashKey returns the key of the network in the
+// NetworkConfigHashesAnnot annotation: "<namespace>/<name>", or
+// "cluster:<name>" for cluster networks, which have no namespace
+func NetworkConfigHashKey(net *v1.NetworkAttachmentDefinition) string {
+	if net.Namespace == "" {
+		return v1.ClusterNetworkPrefix + net.Name
+	}
+	return net.Namespace + "/" + net.Name
+}
+
+// GetNetworkConfigHashes returns the config hashes recorded on the pod when
+// its networks were attached. It returns an empty map when none are recorded.
+func GetNetworkConfigHashes(pod *corev1.Pod) (map[string]string, error) {
+	return parseConfigHashes(pod.Annotations[v1.NetworkConfigHashesAnnot])
+}
+
+func parseConfigHashes(annotation string) (map[string]string, error) {
+	hashes := map[string]string{}
+	if annotation == "" {
+		return hashes, nil
+	}
+	if err := json.Unmarshal([]byte(annotation), &hashes); err != nil {
+		return nil, fmt.Errorf("failed to parse network config hashes: %v", err)
+	}
+	return hashes, nil
+}
+
+// SetNetworkConfigHashes records the config hashes of the networks attached
+// to the pod, keyed by NetworkConfigHashKey. Runtimes call it along with
+// SetNetworkStatus.
+func SetNetworkConfigHashes(client kubernetes.Interface, pod *corev1.Pod, hashes map[string]string) error {
+	return SetNetworkConfigHashesWithContext(context.Background(), client, pod, hashes)
+}
+
+// SetNetworkConfigHashesWithContext is SetNetworkConfigHashes, updating the
+// pod with ctx and logging the update to the logger of ctx
+func SetNetworkConfigHashesWithContext(ctx context.Context, client kubernetes.Interface, pod *corev1.Pod, hashes map[string]string) error {
+	if client == nil {
+		return fmt.Errorf("no client set")
+	}
+	if pod == nil {
+		return fmt.Errorf("no pod set")
+	}
+
+	data, err := json.Marshal(hashes)
+	if err != nil {
+		return fmt.Errorf("SetNetworkConfigHashes: failed to marshal hashes: %v", err)
+	}
+	if _, err := setPodAnnotation(ctx, client, pod, v1.NetworkConfigHashesAnnot, string(data)); err != nil {
+		return fmt.Errorf("SetNetworkConfigHashes: failed to update the pod %s: %v", pod.Name, err)
+	}
+	return nil
+}
+
+// StalePodsForNetwork returns the pods attached to the network with a config
+// hash different from its current one. Pods that did not record a hash for
+// the network are not returned, as their config is unknown.
+func StalePodsForNetwork(net *v1.NetworkAttachmentDefinition, pods []*corev1.Pod) ([]*corev1.Pod, error) {
+	hash, err := ConfigHash(net)
+	if err != nil {
+		return nil, err
+	}
+	key := NetworkConfigHashKey(net)
+
+	var stale []*corev1.Pod
+	for _, pod := range pods {
+		if pod == nil || pod.DeletionTimestamp != nil || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		hashes, err := GetNetworkConfigHashes(pod)
+		if err != nil {
+			return nil, fmt.Errorf("pod %s/%s: %v", pod.Namespace, pod.Name, err)
+		}
+		if recorded, ok := hashes[key]; ok && recorded != hash {
+			stale = append(stale, pod)
+		}
+	}
+	return stale, nil
+}
+
+// RolloutPodOwner triggers a rollout of the Deployment or StatefulSet owning
+// a pod that is stale for the network, by recording the network's current
+// config hash in the NetworkConfigRolloutAnnot annotation of its pod
+// template. Owners must opt in with RolloutOnNetworkChangeAnnot set to
+// "true". It returns whether a rollout was triggered; pods of the same owner
+// only trigger one rollout per config change. The owner is patched at the
+// resourceVersion it was read at and read again on conflicts, so concurrent
+// rollouts for different networks all keep their hash.
+func RolloutPodOwner(ctx context.Context, client kubernetes.Interface, pod *corev1.Pod, net *v1.NetworkAttachmentDefinition) (bool, error) {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return false, nil
+	}
+
+	hash, err := ConfigHash(net)
+	if err != nil {
+		return false, err
+	}
+	key := NetworkConfigHashKey(net)
+
+	apps := client.AppsV1()
+	var kind, name string
+	switch owner.Kind {
+	case "ReplicaSet":
+		rs, err := apps.ReplicaSets(pod.Namespace).Get(ctx, owner.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		rsOwner := metav1.GetControllerOf(rs)
+		if rsOwner == nil || rsOwner.Kind != "Deployment" {
+			return false, nil
+		}
+		kind, name = "Deployment", rsOwner.Name
+	case "StatefulSet":
+		kind, name = "StatefulSet", owner.Name
+	default:
+		return false, nil
+	}
+
+	log := loggerFrom(ctx)
+	attempts := 0
+	triggered := false
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		attempts++
+		if attempts > 1 {
+			log.V(logLevelRetry).Info("Retrying rollout after a conflict", "kind", kind,
+				"owner", klog.KRef(pod.Namespace, name), "network", key, "attempt", attempts)
+		}
+		triggered = false
+
+		var meta, templateMeta metav1.ObjectMeta
+		if kind == "Deployment" {
+			deployment, err := apps.Deployments(pod.Namespace).Get(ctx, name, metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+			meta, templateMeta = deployment.ObjectMeta, deployment.Spec.Template.ObjectMeta
+		} else {
+			statefulSet, err := apps.StatefulSets(pod.Namespace).Get(ctx, name, metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+			meta, templateMeta = statefulSet.ObjectMeta, statefulSet.Spec.Template.ObjectMeta
+		}
+
+		if meta.Annotations[v1.RolloutOnNetworkChangeAnnot] != "true" {
+			return nil
+		}
+		hashes, err := parseConfigHashes(templateMeta.Annotations[v1.NetworkConfigRolloutAnnot])
+		if err != nil {
+			return err
+		}
+		if hashes[key] == hash {
+			return nil
+		}
+		hashes[key] = hash
+
+		value, err := json.Marshal(hashes)
+		if err != nil {
+			return err
+		}
+		patch, err := json.Marshal(map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"resourceVersion": meta.ResourceVersion,
+			},
+			"spec": map[string]interface{}{
+				"template": map[string]interface{}{
+					"metadata": map[string]interface{}{
+						"annotations": map[string]string{v1.NetworkConfigRolloutAnnot: string(value)},
+					},
+				},
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		if kind == "Deployment" {
+			_, err = apps.Deployments(pod.Namespace).Patch(ctx, name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+		} else {
+			_, err = apps.StatefulSets(pod.Namespace).Patch(ctx, name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+		}
+		if err != nil {
+			return err
+		}
+		triggered = true
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to roll out %s %s/%s: %v", kind, pod.Namespace, name, err)
+	}
+	if triggered {
+		log.Info("Rolling out pods with a stale network config", "kind", kind,
+			"owner", klog.KRef(pod.Namespace, name), "network", key, "hash", hash)
+	}
+	return triggered, nil
+}
@@ -0,0 +1,201 @@
+// Copyright (c) 2024 Kubernetes Network Plumbing Working Group
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/logr/funcr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	v1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func hashNAD(namespace, config string) *v1.NetworkAttachmentDefinition {
+	return &v1.NetworkAttachmentDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "net1", Namespace: namespace},
+		Spec:       v1.NetworkAttachmentDefinitionSpec{Config: config},
+	}
+}
+
+func controllerRef(kind, name string) []metav1.OwnerReference {
+	controller := true
+	return []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: kind, Name: name, Controller: &controller}}
+}
+
+var _ = Describe("Network config hashes", func() {
+	It("hashes the canonical config", func() {
+		hash, err := ConfigHash(hashNAD("default", `{"cniVersion": "1.0.0", "type": "bridge", "mtu": 1500}`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(hash).To(HavePrefix("sha256:"))
+
+		same, err := ConfigHash(hashNAD("default", `{"mtu": 1.5e3, "name": "net1", "type": "bridge", "cniVersion": "1.0.0"}`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(same).To(Equal(hash))
+
+		other, err := ConfigHash(hashNAD("default", `{"cniVersion": "1.0.0", "type": "bridge", "mtu": 9000}`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(other).NotTo(Equal(hash))
+
+		empty, err := ConfigHash(hashNAD("default", ""))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(empty).To(BeEmpty())
+
+		_, err = ConfigHash(hashNAD("default", "{"))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("keys cluster networks by their reference", func() {
+		Expect(NetworkConfigHashKey(hashNAD("default", ""))).To(Equal("default/net1"))
+		Expect(NetworkConfigHashKey(hashNAD("", ""))).To(Equal("cluster:net1"))
+	})
+
+	It("records hashes and finds stale pods", func() {
+		nad := hashNAD("default", `{"cniVersion": "1.0.0", "type": "bridge"}`)
+		hash, err := ConfigHash(nad)
+		Expect(err).NotTo(HaveOccurred())
+
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "current", Namespace: "default"}}
+		client := fake.NewSimpleClientset(pod)
+		Expect(SetNetworkConfigHashes(client, pod, map[string]string{"default/net1": hash})).To(Succeed())
+		current, err := client.CoreV1().Pods("default").Get(context.TODO(), "current", metav1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		hashes, err := GetNetworkConfigHashes(current)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(hashes).To(Equal(map[string]string{"default/net1": hash}))
+
+		stale := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "stale", Namespace: "tenant",
+			Annotations: map[string]string{v1.NetworkConfigHashesAnnot: `{"default/net1": "sha256:old"}`}}}
+		unknown := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "unknown", Namespace: "default"}}
+		finished := stale.DeepCopy()
+		finished.Status.Phase = corev1.PodSucceeded
+
+		pods, err := StalePodsForNetwork(nad, []*corev1.Pod{current, stale, unknown, finished})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pods).To(Equal([]*corev1.Pod{stale}))
+
+		broken := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "broken", Namespace: "default",
+			Annotations: map[string]string{v1.NetworkConfigHashesAnnot: "["}}}
+		_, err = StalePodsForNetwork(nad, []*corev1.Pod{broken})
+		Expect(err).To(MatchError(ContainSubstring("pod default/broken")))
+	})
+
+	It("logs retried hash updates to the logger of the context", func() {
+		var logs []string
+		ctx := logr.NewContext(context.Background(), funcr.New(func(prefix, args string) {
+			logs = append(logs, args)
+		}, funcr.Options{Verbosity: logLevelRetry}))
+
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod1", Namespace: "default"}}
+		client := fake.NewSimpleClientset(pod)
+		conflicts := 1
+		client.PrependReactor("update", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			if conflicts == 0 {
+				return false, nil, nil
+			}
+			conflicts--
+			return true, nil, apierrors.NewConflict(schema.GroupResource{Resource: "pods"}, "pod1", nil)
+		})
+		Expect(SetNetworkConfigHashesWithContext(ctx, client, pod, map[string]string{"default/net1": "sha256:new"})).To(Succeed())
+		Expect(logs).To(ContainElement(ContainSubstring(`"annotation"="` + v1.NetworkConfigHashesAnnot + `"`)))
+	})
+
+	It("rolls out the owners that opted in", func() {
+		nad := hashNAD("default", `{"cniVersion": "1.0.0", "type": "bridge"}`)
+		hash, err := ConfigHash(nad)
+		Expect(err).NotTo(HaveOccurred())
+
+		deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default",
+			Annotations: map[string]string{v1.RolloutOnNetworkChangeAnnot: "true"}}}
+		replicaSet := &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "default",
+			OwnerReferences: controllerRef("Deployment", "web")}}
+		statefulSet := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default"}}
+		client := fake.NewSimpleClientset(deployment, replicaSet, statefulSet)
+		ctx := context.TODO()
+
+		webPod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-1-a", Namespace: "default", OwnerReferences: controllerRef("ReplicaSet", "web-1")}}
+		triggered, err := RolloutPodOwner(ctx, client, webPod, nad)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(triggered).To(BeTrue())
+
+		deployment, err = client.AppsV1().Deployments("default").Get(ctx, "web", metav1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(deployment.Spec.Template.Annotations[v1.NetworkConfigRolloutAnnot]).To(Equal(`{"default/net1":"` + hash + `"}`))
+
+		// the other pods of the deployment do not trigger another rollout
+		triggered, err = RolloutPodOwner(ctx, client, webPod, nad)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(triggered).To(BeFalse())
+
+		// owners that did not opt in and bare pods are left alone
+		dbPod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "db-0", Namespace: "default", OwnerReferences: controllerRef("StatefulSet", "db")}}
+		triggered, err = RolloutPodOwner(ctx, client, dbPod, nad)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(triggered).To(BeFalse())
+
+		triggered, err = RolloutPodOwner(ctx, client, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "bare", Namespace: "default"}}, nad)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(triggered).To(BeFalse())
+	})
+
+	It("keeps the hashes of concurrent rollouts", func() {
+		nad := hashNAD("default", `{"cniVersion": "1.0.0", "type": "bridge"}`)
+		hash, err := ConfigHash(nad)
+		Expect(err).NotTo(HaveOccurred())
+
+		deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default",
+			Annotations: map[string]string{v1.RolloutOnNetworkChangeAnnot: "true"}}}
+		replicaSet := &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "default",
+			OwnerReferences: controllerRef("Deployment", "web")}}
+		client := fake.NewSimpleClientset(deployment, replicaSet)
+		ctx := context.TODO()
+
+		// another network rolls the deployment out between the read and the
+		// patch of the first attempt
+		var patches []string
+		client.PrependReactor("patch", "deployments", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			patches = append(patches, string(action.(k8stesting.PatchAction).GetPatch()))
+			if len(patches) > 1 {
+				return false, nil, nil
+			}
+			concurrent := deployment.DeepCopy()
+			concurrent.Spec.Template.Annotations = map[string]string{v1.NetworkConfigRolloutAnnot: `{"default/net2":"sha256:net2"}`}
+			Expect(client.Tracker().Update(appsv1.SchemeGroupVersion.WithResource("deployments"), concurrent, "default")).To(Succeed())
+			return true, nil, apierrors.NewConflict(schema.GroupResource{Group: "apps", Resource: "deployments"}, "web", nil)
+		})
+
+		webPod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-1-a", Namespace: "default", OwnerReferences: controllerRef("ReplicaSet", "web-1")}}
+		triggered, err := RolloutPodOwner(ctx, client, webPod, nad)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(triggered).To(BeTrue())
+		Expect(patches).To(HaveLen(2))
+		Expect(patches[0]).To(ContainSubstring(`"resourceVersion"`))
+
+		deployment, err = client.AppsV1().Deployments("default").Get(ctx, "web", metav1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(deployment.Spec.Template.Annotations[v1.NetworkConfigRolloutAnnot]).To(MatchJSON(`{"default/net1":"` + hash + `","default/net2":"sha256:net2"}`))
+	})
+})
@@ -0,0 +1,240 @@
+// Copyright (c) 2024 Kubernetes Network Plumbing Working Group
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	v1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+)
+
+// Difference is a difference between two NetworkAttachmentDefinitions
+type Difference struct {
+	// Path locates the value, like "plugins[0].ipam.subnet"
+	Path string
+	// From and To are the values of the first and second networks, nil when
+	// the value is absent
+	From interface{}
+	To   interface{}
+}
+
+func (d Difference) String() string {
+	return fmt.Sprintf("%s: %s -> %s", d.Path, diffValue(d.From), diffValue(d.To))
+}
+
+func diffValue(value interface{}) string {
+	if value == nil {
+		return "<none>"
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(data)
+}
+
+// SemanticEqual reports whether two NetworkAttachmentDefinitions configure
+// the same network, see Diff
+func SemanticEqual(a, b *v1.NetworkAttachmentDefinition) bool {
+	return len(Diff(a, b)) == 0
+}
+
+// Diff compares the CNI configurations and the resource names of two
+// NetworkAttachmentDefinitions field by field. Formatting, key order and
+// the defaulted network name and cniVersion are ignored, and single plugin
+// configurations compare as lists of one plugin. Plugins of different types
+// are reported as a whole. Configs that are not JSON objects are compared as
+// strings.
+func Diff(a, b *v1.NetworkAttachmentDefinition) []Difference {
+	var diffs []Difference
+	if from, to := a.Annotations[v1.ResourceNameAnnot], b.Annotations[v1.ResourceNameAnnot]; from != to {
+		diffs = append(diffs, Difference{Path: "metadata.annotations[" + v1.ResourceNameAnnot + "]", From: optional(from), To: optional(to)})
+	}
+
+	a, b = a.DeepCopy(), b.DeepCopy()
+	v1.SetObjectDefaults_NetworkAttachmentDefinition(a)
+	v1.SetObjectDefaults_NetworkAttachmentDefinition(b)
+	if a.Spec.Config == b.Spec.Config {
+		return diffs
+	}
+
+	from, fromErr := comparableConfig(a.Spec.Config)
+	to, toErr := comparableConfig(b.Spec.Config)
+	if fromErr != nil || toErr != nil {
+		return append(diffs, Difference{Path: "spec.config", From: optional(a.Spec.Config), To: optional(b.Spec.Config)})
+	}
+	return append(diffs, diffConfigs(from, to)...)
+}
+
+func optional(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+// comparableConfig decodes the canonical form of a config, so equal numbers
+// are equal json.Numbers, and turns single plugin configs into lists
+func comparableConfig(config string) (map[string]interface{}, error) {
+	if config == "" {
+		return map[string]interface{}{}, nil
+	}
+
+	canonical, err := CanonicalizeConfig([]byte(config))
+	if err != nil {
+		return nil, err
+	}
+	rawConfig, err := decodeConfig(canonical)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := rawConfig["plugins"]; !ok {
+		plugin := map[string]interface{}{}
+		list := map[string]interface{}{"plugins": []interface{}{plugin}}
+		for k, v := range rawConfig {
+			switch k {
+			case "name", "cniVersion", "cniVersions", "disableCheck", "disableGC", "loadOnlyInlinedPlugins":
+				list[k] = v
+			default:
+				plugin[k] = v
+			}
+		}
+		rawConfig = list
+	}
+
+	// the network name and version are passed to every plugin
+	if plugins, ok := rawConfig["plugins"].([]interface{}); ok {
+		for _, p := range plugins {
+			if plugin, ok := p.(map[string]interface{}); ok {
+				for _, k := range []string{"name", "cniVersion"} {
+					if v, ok := plugin[k]; ok && valuesEqual(v, rawConfig[k]) {
+						delete(plugin, k)
+					}
+				}
+			}
+		}
+	}
+	return rawConfig, nil
+}
+
+func diffConfigs(from, to map[string]interface{}) []Difference {
+	fromPlugins, fromOk := from["plugins"].([]interface{})
+	toPlugins, toOk := to["plugins"].([]interface{})
+	if !fromOk || !toOk {
+		return diffValues("", from, to)
+	}
+
+	fromRest := withoutKey(from, "plugins")
+	toRest := withoutKey(to, "plugins")
+	diffs := diffValues("", fromRest, toRest)
+	for i := 0; i < len(fromPlugins) || i < len(toPlugins); i++ {
+		path := fmt.Sprintf("plugins[%d]", i)
+		switch {
+		case i >= len(toPlugins):
+			diffs = append(diffs, Difference{Path: path, From: fromPlugins[i]})
+		case i >= len(fromPlugins):
+			diffs = append(diffs, Difference{Path: path, To: toPlugins[i]})
+		case !valuesEqual(pluginType(fromPlugins[i]), pluginType(toPlugins[i])):
+			diffs = append(diffs, Difference{Path: path, From: fromPlugins[i], To: toPlugins[i]})
+		default:
+			diffs = append(diffs, diffValues(path, fromPlugins[i], toPlugins[i])...)
+		}
+	}
+	return diffs
+}
+
+func pluginType(plugin interface{}) interface{} {
+	if p, ok := plugin.(map[string]interface{}); ok {
+		return p["type"]
+	}
+	return nil
+}
+
+func withoutKey(m map[string]interface{}, key string) map[string]interface{} {
+	out := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		if k != key {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// diffValues compares objects key by key and other values as a whole
+func diffValues(path string, from, to interface{}) []Difference {
+	fromMap, fromOk := from.(map[string]interface{})
+	toMap, toOk := to.(map[string]interface{})
+	if !fromOk || !toOk {
+		if valuesEqual(from, to) {
+			return nil
+		}
+		return []Difference{{Path: path, From: from, To: to}}
+	}
+
+	keys := make([]string, 0, len(fromMap)+len(toMap))
+	for k := range fromMap {
+		keys = append(keys, k)
+	}
+	for k := range toMap {
+		if _, ok := fromMap[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	var diffs []Difference
+	for _, k := range keys {
+		keyPath := k
+		if path != "" {
+			keyPath = path + "." + k
+		}
+		diffs = append(diffs, diffValues(keyPath, fromMap[k], toMap[k])...)
+	}
+	return diffs
+}
+
+// valuesEqual compares decoded canonical JSON values
+func valuesEqual(a, b interface{}) bool {
+	switch a := a.(type) {
+	case map[string]interface{}:
+		b, ok := b.(map[string]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for k, v := range a {
+			w, ok := b[k]
+			if !ok || !valuesEqual(v, w) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		b, ok := b.([]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !valuesEqual(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return a == b
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2024 Kubernetes Network Plumbing Working Group
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+func diffNAD(config string, annotations map[string]string) *v1.NetworkAttachmentDefinition {
+	return &v1.NetworkAttachmentDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "net1", Namespace: "default", Annotations: annotations},
+		Spec:       v1.NetworkAttachmentDefinitionSpec{Config: config},
+	}
+}
+
+var _ = Describe("Network diff", func() {
+	table.DescribeTable("ignores formatting and defaults",
+		func(a, b string) {
+			Expect(Diff(diffNAD(a, nil), diffNAD(b, nil))).To(BeEmpty())
+			Expect(SemanticEqual(diffNAD(a, nil), diffNAD(b, nil))).To(BeTrue())
+		},
+		table.Entry("key order and whitespace",
+			`{"cniVersion": "1.0.0", "name": "net1", "type": "bridge", "ipam": {"type": "dhcp"}}`,
+			`{"ipam":{"type":"dhcp"},"type":"bridge","name":"net1","cniVersion":"1.0.0"}`),
+		table.Entry("defaulted name and cniVersion",
+			`{"cniVersion": "v1.0", "type": "bridge"}`,
+			`{"cniVersion": "1.0.0", "name": "net1", "type": "bridge"}`),
+		table.Entry("number formatting",
+			`{"cniVersion": "1.0.0", "type": "vlan", "vlanId": 100, "mtu": 1.5e3}`,
+			`{"cniVersion": "1.0.0", "type": "vlan", "vlanId": 100.0, "mtu": 1500}`),
+		table.Entry("single plugin config and list of one plugin",
+			`{"cniVersion": "1.0.0", "name": "net1", "type": "bridge"}`,
+			`{"cniVersion": "1.0.0", "name": "net1", "plugins": [{"type": "bridge"}]}`),
+		table.Entry("empty specs", "", ""),
+	)
+
+	It("reports changed, added and removed fields", func() {
+		a := diffNAD(`{"cniVersion": "1.0.0", "plugins": [{"type": "bridge", "ipam": {"type": "host-local", "subnet": "10.1.0.0/24"}, "mtu": 1500}]}`, nil)
+		b := diffNAD(`{"cniVersion": "1.0.0", "plugins": [{"type": "bridge", "ipam": {"type": "host-local", "subnet": "10.2.0.0/24"}, "isGateway": true}]}`, nil)
+
+		diffs := Diff(a, b)
+		Expect(diffs).To(HaveLen(3))
+		Expect(diffs[0].String()).To(Equal(`plugins[0].ipam.subnet: "10.1.0.0/24" -> "10.2.0.0/24"`))
+		Expect(diffs[1].String()).To(Equal(`plugins[0].isGateway: <none> -> true`))
+		Expect(diffs[2].String()).To(Equal(`plugins[0].mtu: 1500 -> <none>`))
+		Expect(SemanticEqual(a, b)).To(BeFalse())
+	})
+
+	It("reports plugins of another type as a whole", func() {
+		a := diffNAD(`{"cniVersion": "1.0.0", "plugins": [{"type": "bridge"}, {"type": "tuning"}]}`, nil)
+		b := diffNAD(`{"cniVersion": "1.1.0", "plugins": [{"type": "bridge"}, {"type": "portmap"}, {"type": "tuning"}]}`, nil)
+
+		diffs := Diff(a, b)
+		Expect(diffs).To(HaveLen(3))
+		Expect(diffs[0].Path).To(Equal("cniVersion"))
+		Expect(diffs[1].String()).To(Equal(`plugins[1]: {"type":"tuning"} -> {"type":"portmap"}`))
+		Expect(diffs[2].String()).To(Equal(`plugins[2]: <none> -> {"type":"tuning"}`))
+	})
+
+	It("compares the resource name and configs that are not JSON", func() {
+		a := diffNAD("not json", map[string]string{v1.ResourceNameAnnot: "intel.com/sriov"})
+		b := diffNAD(`{"type": "sriov"}`, map[string]string{"other": "ignored"})
+
+		diffs := Diff(a, b)
+		Expect(diffs).To(HaveLen(2))
+		Expect(diffs[0].String()).To(Equal(`metadata.annotations[k8s.v1.cni.cncf.io/resourceName]: "intel.com/sriov" -> <none>`))
+		Expect(diffs[1].Path).To(Equal("spec.config"))
+	})
+})
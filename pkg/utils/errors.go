@@ -0,0 +1,107 @@
+// Copyright (c) 2024 Kubernetes Network Plumbing Working Group
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"fmt"
+)
+
+// InvalidAnnotationError is returned when a network selection annotation
+// cannot be parsed. Use errors.As to tell it apart from the other errors.
+type InvalidAnnotationError struct {
+	// Annotation is the value of the annotation
+	Annotation string
+	// Index is the index of the invalid network selection element, or -1
+	// when the annotation as a whole is invalid
+	Index int
+	// Position is the byte offset of the error in Annotation, or -1 when
+	// unknown
+	Position int64
+	// Err is the cause of the error
+	Err error
+}
+
+func (e *InvalidAnnotationError) Error() string {
+	return fmt.Sprintf("parsePodNetworkAnnotation: %v", e.Err)
+}
+
+func (e *InvalidAnnotationError) Unwrap() error { return e.Err }
+
+// NetworkNotFoundError is returned when a network does not exist: in the
+// API when FromAPI is set, see ResolveNetworkSelections, or in the CNI
+// configuration files of ConfDir otherwise
+type NetworkNotFoundError struct {
+	// Name is the name of the network, empty when any network was looked up
+	Name string
+	// Namespace is the namespace of the network, empty for cluster networks
+	// and networks of ConfDir
+	Namespace string
+	// FromAPI is set when the network was looked up in the API rather than
+	// in ConfDir
+	FromAPI bool
+	// ConfDir is the CNI configuration directory, empty when FromAPI is set
+	ConfDir string
+	// NoNetworks is set when ConfDir has no configuration files or could not
+	// be listed
+	NoNetworks bool
+	// Err is the cause of the error, if any, such as a failure to list
+	// ConfDir or the load errors of invalid files that may define the
+	// network
+	Err error
+}
+
+func (e *NetworkNotFoundError) Error() string {
+	// the message of an empty ConfDir matches the untyped error of earlier
+	// releases, the cause is left to Unwrap
+	if e.NoNetworks {
+		return fmt.Sprintf("No networks found in %s", e.ConfDir)
+	}
+	var msg string
+	switch {
+	case e.FromAPI && e.Namespace == "":
+		msg = fmt.Sprintf("cluster network %s not found", e.Name)
+	case e.FromAPI:
+		msg = fmt.Sprintf("network %s/%s not found", e.Namespace, e.Name)
+	case e.Name == "":
+		msg = fmt.Sprintf("No networks found in %s", e.ConfDir)
+	default:
+		msg = fmt.Sprintf("no network available in the name %s in cni dir %s", e.Name, e.ConfDir)
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *NetworkNotFoundError) Unwrap() error { return e.Err }
+
+// InvalidConfigError is returned when a CNI configuration, from a file or
+// from the spec of a NetworkAttachmentDefinition, is malformed
+type InvalidConfigError struct {
+	// Path is the path of the configuration file, empty for configurations
+	// from a spec
+	Path string
+	// Err is the cause of the error
+	Err error
+}
+
+func (e *InvalidConfigError) Error() string {
+	if e.Path == "" {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("Error loading CNI config file %s: %v", e.Path, e.Err)
+}
+
+func (e *InvalidConfigError) Unwrap() error { return e.Err }
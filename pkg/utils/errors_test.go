@@ -0,0 +1,127 @@
+// Copyright (c) 2024 Kubernetes Network Plumbing Working Group
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Typed errors", func() {
+	table.DescribeTable("locate invalid annotations",
+		func(annotation string, index int, position int64) {
+			_, err := ParseNetworkAnnotation(annotation, "default")
+			var annotErr *InvalidAnnotationError
+			Expect(errors.As(err, &annotErr)).To(BeTrue())
+			Expect(annotErr.Annotation).To(Equal(annotation))
+			Expect(annotErr.Index).To(Equal(index))
+			Expect(annotErr.Position).To(Equal(position))
+		},
+		table.Entry("with a JSON syntax error", `[{"name": "net1"`, -1, int64(16)),
+		table.Entry("with too many slashes", "net1, a/b/c", 1, int64(9)),
+		table.Entry("with too many ats", "net1@eth1, net2@a@b", 1, int64(17)),
+		table.Entry("with an invalid namespace", "net1,  Ns/net2", 1, int64(7)),
+		table.Entry("with an invalid interface", "net1, ns/net2@ Eth1", 1, int64(15)),
+		table.Entry("with a namespaced cluster network", "net1,cluster:ns/net2", 1, int64(13)),
+		table.Entry("with an unknown scope", `[{"name": "net1", "scope": "global"}]`, 0, int64(-1)),
+	)
+
+	It("keeps the messages and causes of invalid annotations", func() {
+		_, err := ParseNetworkAnnotation(`[{"name": "net1"`, "default")
+		Expect(err).To(MatchError(HavePrefix("parsePodNetworkAnnotation: failed to parse pod Network Attachment Selection Annotation JSON format: ")))
+		var syntaxErr *json.SyntaxError
+		Expect(errors.As(err, &syntaxErr)).To(BeTrue())
+
+		_, err = ParseNetworkAnnotation("", "default")
+		var annotErr *InvalidAnnotationError
+		Expect(errors.As(err, &annotErr)).To(BeTrue())
+		Expect(annotErr.Index).To(Equal(-1))
+	})
+
+	It("tells missing networks from malformed config files", func() {
+		tmpDir, err := os.MkdirTemp("", "multus-tmp")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(tmpDir)
+
+		_, err = GetCNIConfigFromFile("net1", tmpDir)
+		var notFoundErr *NetworkNotFoundError
+		Expect(errors.As(err, &notFoundErr)).To(BeTrue())
+		Expect(notFoundErr.Name).To(Equal("net1"))
+		Expect(notFoundErr.ConfDir).To(Equal(tmpDir))
+		Expect(notFoundErr.NoNetworks).To(BeTrue())
+		Expect(err).To(MatchError("No networks found in " + tmpDir))
+
+		Expect(os.WriteFile(filepath.Join(tmpDir, "10-net2.conf"), []byte(`{"cniVersion": "1.0.0", "name": "net2", "type": "bridge"}`), 0644)).To(Succeed())
+		net := &v1.NetworkAttachmentDefinition{ObjectMeta: metav1.ObjectMeta{Name: "net1", Namespace: "default"}}
+		_, err = GetCNIConfig(net, tmpDir)
+		Expect(errors.As(err, &notFoundErr)).To(BeTrue())
+		Expect(err).To(MatchError("GetCNIConfig: err in GetCNIConfigFromFile: no network available in the name net1 in cni dir " + tmpDir))
+
+		path := filepath.Join(tmpDir, "00-broken.conf")
+		Expect(os.WriteFile(path, []byte(`{"name": "net1"}`), 0644)).To(Succeed())
+		_, err = GetCNIConfig(net, tmpDir)
+		var configErr *InvalidConfigError
+		Expect(errors.As(err, &configErr)).To(BeTrue())
+		Expect(configErr.Path).To(Equal(path))
+		Expect(errors.As(err, &notFoundErr)).To(BeFalse())
+	})
+
+	It("reports missing networks without a config dir as config file lookups", func() {
+		_, err := GetCNIConfigFromFile("net1", "")
+		var notFoundErr *NetworkNotFoundError
+		Expect(errors.As(err, &notFoundErr)).To(BeTrue())
+		Expect(notFoundErr.FromAPI).To(BeFalse())
+		Expect(err).To(MatchError("No networks found in "))
+
+		err = &NetworkNotFoundError{Name: "net1", FromAPI: true}
+		Expect(err).To(MatchError("cluster network net1 not found"))
+	})
+
+	It("reports malformed specs as invalid configs", func() {
+		net := &v1.NetworkAttachmentDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "net1", Namespace: "default"},
+			Spec:       v1.NetworkAttachmentDefinitionSpec{Config: `{"type": `},
+		}
+		_, err := GetCNIConfig(net, "")
+		var configErr *InvalidConfigError
+		Expect(errors.As(err, &configErr)).To(BeTrue())
+		Expect(configErr.Path).To(BeEmpty())
+		Expect(err).To(MatchError(HavePrefix("GetCNIConfig: err in getCNIConfigFromSpec: failed to unmarshal Spec.Config: ")))
+	})
+
+	It("reports malformed files of the config dir cache as invalid configs", func() {
+		tmpDir, err := os.MkdirTemp("", "multus-tmp")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(tmpDir)
+		Expect(os.WriteFile(filepath.Join(tmpDir, "10-broken.conf"), []byte(`{"name": "net1"}`), 0644)).To(Succeed())
+
+		cache, err := NewConfDirCache(tmpDir, 0)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = cache.GetCNIConfigFromFile("net1")
+		var configErr *InvalidConfigError
+		Expect(errors.As(err, &configErr)).To(BeTrue())
+		Expect(configErr.Path).To(Equal(filepath.Join(tmpDir, "10-broken.conf")))
+	})
+})
@@ -0,0 +1,99 @@
+// Copyright (c) 2024 Kubernetes Network Plumbing Working Group
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+
+	v1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+)
+
+// The helpers below record the network attachment lifecycle as Events on
+// the pod and on the NetworkAttachmentDefinition, with the reasons defined
+// in the v1 package. Events on networks require the scheme of the recorder
+// to include the v1 types, see the AddToScheme of the clientset's scheme
+// package. Nothing is recorded when the recorder is nil. Cluster networks
+// converted with ClusterNetworkToNetworkAttachmentDefinition have no
+// namespace and are not real objects, so only the pod gets their events.
+
+// RecordNetworkAttached records that the network was attached to the pod as
+// the interface ifName
+func RecordNetworkAttached(recorder record.EventRecorder, pod *corev1.Pod, net *v1.NetworkAttachmentDefinition, ifName string) {
+	if recorder == nil || pod == nil || net == nil {
+		return
+	}
+	recorder.Eventf(pod, corev1.EventTypeNormal, v1.NetworkAttachedReason,
+		"Attached network %s as interface %s", networkRef(net), ifName)
+	if net.Namespace == "" {
+		return
+	}
+	recorder.Eventf(net, corev1.EventTypeNormal, v1.NetworkAttachedReason,
+		"Attached to pod %s/%s as interface %s", pod.Namespace, pod.Name, ifName)
+}
+
+// RecordNetworkAttachFailed records that the network failed to attach to the
+// pod. net may be nil when the network could not be found, in which case
+// only the pod gets the event.
+func RecordNetworkAttachFailed(recorder record.EventRecorder, pod *corev1.Pod, net *v1.NetworkAttachmentDefinition, err error) {
+	if recorder == nil || pod == nil {
+		return
+	}
+	if net == nil {
+		recorder.Eventf(pod, corev1.EventTypeWarning, v1.NetworkAttachFailedReason,
+			"Failed to attach network: %v", err)
+		return
+	}
+	recorder.Eventf(pod, corev1.EventTypeWarning, v1.NetworkAttachFailedReason,
+		"Failed to attach network %s: %v", networkRef(net), err)
+	if net.Namespace == "" {
+		return
+	}
+	recorder.Eventf(net, corev1.EventTypeWarning, v1.NetworkAttachFailedReason,
+		"Failed to attach to pod %s/%s: %v", pod.Namespace, pod.Name, err)
+}
+
+// RecordNetworkStatusUpdated records that the network status of the pod was
+// set to statuses, see SetNetworkStatus
+func RecordNetworkStatusUpdated(recorder record.EventRecorder, pod *corev1.Pod, statuses []v1.NetworkStatus) {
+	if recorder == nil || pod == nil {
+		return
+	}
+	names := make([]string, 0, len(statuses))
+	for _, status := range statuses {
+		names = append(names, status.Name)
+	}
+	recorder.Eventf(pod, corev1.EventTypeNormal, v1.NetworkStatusUpdatedReason,
+		"Updated the network status with networks [%s]", strings.Join(names, ", "))
+}
+
+// RecordInvalidNetworkAnnotation records that the network selection
+// annotation of the pod could not be parsed, see ParsePodNetworkAnnotation
+func RecordInvalidNetworkAnnotation(recorder record.EventRecorder, pod *corev1.Pod, err error) {
+	if recorder == nil || pod == nil {
+		return
+	}
+	recorder.Eventf(pod, corev1.EventTypeWarning, v1.InvalidNetworkAnnotationReason,
+		"Invalid %s annotation: %v", v1.NetworkAttachmentAnnot, err)
+}
+
+func networkRef(net *v1.NetworkAttachmentDefinition) string {
+	if net.Namespace == "" {
+		return net.Name
+	}
+	return net.Namespace + "/" + net.Name
+}
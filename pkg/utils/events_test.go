@@ -0,0 +1,97 @@
+// Copyright (c) 2024 Kubernetes Network Plumbing Working Group
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	v1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Events", func() {
+	var recorder *record.FakeRecorder
+	var pod *corev1.Pod
+	var net *v1.NetworkAttachmentDefinition
+
+	events := func() []string {
+		var recorded []string
+		for len(recorder.Events) > 0 {
+			recorded = append(recorded, <-recorder.Events)
+		}
+		return recorded
+	}
+
+	BeforeEach(func() {
+		recorder = record.NewFakeRecorder(10)
+		pod = &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod1", Namespace: "default"}}
+		net = &v1.NetworkAttachmentDefinition{ObjectMeta: metav1.ObjectMeta{Name: "net1", Namespace: "infra"}}
+	})
+
+	It("records attached networks on the pod and the network", func() {
+		RecordNetworkAttached(recorder, pod, net, "net1")
+		Expect(events()).To(Equal([]string{
+			"Normal NetworkAttached Attached network infra/net1 as interface net1",
+			"Normal NetworkAttached Attached to pod default/pod1 as interface net1",
+		}))
+	})
+
+	It("records attach failures", func() {
+		RecordNetworkAttachFailed(recorder, pod, net, fmt.Errorf("plugin failed"))
+		RecordNetworkAttachFailed(recorder, pod, nil, fmt.Errorf("network not found"))
+		Expect(events()).To(Equal([]string{
+			"Warning NetworkAttachFailed Failed to attach network infra/net1: plugin failed",
+			"Warning NetworkAttachFailed Failed to attach to pod default/pod1: plugin failed",
+			"Warning NetworkAttachFailed Failed to attach network: network not found",
+		}))
+	})
+
+	It("records events of cluster networks only on the pod", func() {
+		cnet := ClusterNetworkToNetworkAttachmentDefinition(&v1.ClusterNetworkAttachmentDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-net"},
+		})
+		RecordNetworkAttached(recorder, pod, cnet, "net1")
+		RecordNetworkAttachFailed(recorder, pod, cnet, fmt.Errorf("plugin failed"))
+		Expect(events()).To(Equal([]string{
+			"Normal NetworkAttached Attached network cluster-net as interface net1",
+			"Warning NetworkAttachFailed Failed to attach network cluster-net: plugin failed",
+		}))
+	})
+
+	It("records network status updates and invalid annotations", func() {
+		RecordNetworkStatusUpdated(recorder, pod, []v1.NetworkStatus{{Name: "default/kindnet"}, {Name: "infra/net1"}})
+		_, err := ParseNetworkAnnotation("a/b/c", "default")
+		Expect(err).To(HaveOccurred())
+		RecordInvalidNetworkAnnotation(recorder, pod, err)
+		Expect(events()).To(Equal([]string{
+			"Normal NetworkStatusUpdated Updated the network status with networks [default/kindnet, infra/net1]",
+			"Warning InvalidNetworkAnnotation Invalid k8s.v1.cni.cncf.io/networks annotation: " + err.Error(),
+		}))
+	})
+
+	It("ignores a nil recorder", func() {
+		RecordNetworkAttached(nil, pod, net, "net1")
+		RecordNetworkAttachFailed(nil, pod, net, fmt.Errorf("plugin failed"))
+		RecordNetworkStatusUpdated(nil, pod, nil)
+		RecordInvalidNetworkAnnotation(nil, pod, fmt.Errorf("invalid"))
+		Expect(events()).To(BeEmpty())
+	})
+})
@@ -0,0 +1,139 @@
+// Copyright (c) 2024 Kubernetes Network Plumbing Working Group
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"errors"
+	"fmt"
+
+	cnitypes "github.com/containernetworking/cni/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	v1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+)
+
+// ContainerIDFunc returns the sandbox container ID the runtime attached the
+// pod's networks to. An empty ID means the pod has no sandbox on this node.
+type ContainerIDFunc func(pod *corev1.Pod) (string, error)
+
+// ValidAttachmentsForNetwork computes the "cni.dev/valid-attachments" list
+// passed to CNI GC for the NetworkAttachmentDefinition: one entry for every
+// interface a non-terminated pod has on the network. Interfaces are taken
+// from the pod's network-status annotation, then from the interface
+// requested in the selection, and finally from the "net<N>" name Multus
+// assigns to the N-th selected network.
+//
+// An error is returned when the attachments of any pod cannot be
+// determined; callers must not garbage-collect in that case, as valid
+// attachments could be deleted.
+func ValidAttachmentsForNetwork(nad *v1.NetworkAttachmentDefinition, pods []*corev1.Pod, containerIDFn ContainerIDFunc) ([]cnitypes.GCAttachment, error) {
+	if nad == nil {
+		return nil, fmt.Errorf("no network attachment definition set")
+	}
+	if containerIDFn == nil {
+		return nil, fmt.Errorf("no container ID function set")
+	}
+
+	var attachments []cnitypes.GCAttachment
+	var errs []error
+	for _, pod := range pods {
+		if pod == nil || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+
+		ifNames, err := podInterfacesForNetwork(nad, pod)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("pod %s/%s: %v", pod.Namespace, pod.Name, err))
+			continue
+		}
+		if len(ifNames) == 0 {
+			continue
+		}
+
+		containerID, err := containerIDFn(pod)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("pod %s/%s: failed to get container ID: %v", pod.Namespace, pod.Name, err))
+			continue
+		}
+		if containerID == "" {
+			continue
+		}
+
+		for _, ifName := range ifNames {
+			attachments = append(attachments, cnitypes.GCAttachment{
+				ContainerID: containerID,
+				IfName:      ifName,
+			})
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, utilerrors.NewAggregate(errs)
+	}
+	return attachments, nil
+}
+
+// podInterfacesForNetwork returns the names of the pod interfaces attached
+// to the NetworkAttachmentDefinition
+func podInterfacesForNetwork(nad *v1.NetworkAttachmentDefinition, pod *corev1.Pod) ([]string, error) {
+	selections, err := ParsePodNetworkAnnotation(pod)
+	if err != nil {
+		var noNetworkErr *v1.NoK8sNetworkError
+		if errors.As(err, &noNetworkErr) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	qualifiedName := fmt.Sprintf("%s/%s", nad.Namespace, nad.Name)
+	seen := make(map[string]bool)
+	var ifNames []string
+	add := func(ifName string) {
+		if ifName != "" && !seen[ifName] {
+			seen[ifName] = true
+			ifNames = append(ifNames, ifName)
+		}
+	}
+
+	// The network status reflects what was actually attached
+	if _, ok := pod.Annotations[v1.NetworkStatusAnnot]; ok {
+		statuses, err := GetNetworkStatus(pod)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse network status: %v", err)
+		}
+		for _, status := range statuses {
+			if status.Name == qualifiedName || (nad.Namespace == pod.Namespace && status.Name == nad.Name) {
+				add(status.Interface)
+			}
+		}
+	}
+
+	for i, sel := range selections {
+		if sel.Name != nad.Name || sel.Namespace != nad.Namespace {
+			continue
+		}
+		// Selections of the same network without a request are not told
+		// apart in the network status, so their default names are always
+		// added
+		if sel.InterfaceRequest != "" {
+			add(sel.InterfaceRequest)
+		} else {
+			add(fmt.Sprintf("net%d", i+1))
+		}
+	}
+
+	return ifNames, nil
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2024 Kubernetes Network Plumbing Working Group
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	cnitypes "github.com/containernetworking/cni/pkg/types"
+
+	v1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CNI GC valid attachments", func() {
+	nad := &v1.NetworkAttachmentDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "macvlan",
+			Namespace: "testnamespace",
+		},
+	}
+
+	newPod := func(name string, annotations map[string]string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   "testnamespace",
+				UID:         types.UID("uid-" + name),
+				Annotations: annotations,
+			},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+	}
+
+	containerID := func(pod *corev1.Pod) (string, error) {
+		return "sandbox-" + pod.Name, nil
+	}
+
+	It("collects interfaces from network status, requests and default names", func() {
+		withStatus := newPod("with-status", map[string]string{
+			v1.NetworkAttachmentAnnot: "macvlan",
+			v1.NetworkStatusAnnot:     `[{"name": "cbr0", "interface": "eth0"}, {"name": "testnamespace/macvlan", "interface": "net1"}]`,
+		})
+		withRequest := newPod("with-request", map[string]string{
+			v1.NetworkAttachmentAnnot: "other, testnamespace/macvlan@ext0",
+		})
+		withDefault := newPod("with-default", map[string]string{
+			v1.NetworkAttachmentAnnot: "other, macvlan",
+		})
+		unrelated := newPod("unrelated", map[string]string{
+			v1.NetworkAttachmentAnnot: "other",
+		})
+		noNetworks := newPod("no-networks", nil)
+		completed := newPod("completed", map[string]string{
+			v1.NetworkAttachmentAnnot: "macvlan",
+		})
+		completed.Status.Phase = corev1.PodSucceeded
+
+		attachments, err := ValidAttachmentsForNetwork(nad,
+			[]*corev1.Pod{withStatus, withRequest, withDefault, unrelated, noNetworks, completed}, containerID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(attachments).To(Equal([]cnitypes.GCAttachment{
+			{ContainerID: "sandbox-with-status", IfName: "net1"},
+			{ContainerID: "sandbox-with-request", IfName: "ext0"},
+			{ContainerID: "sandbox-with-default", IfName: "net2"},
+		}))
+	})
+
+	It("keeps every attachment of a network selected more than once", func() {
+		repeated := newPod("repeated", map[string]string{
+			v1.NetworkAttachmentAnnot: "macvlan, other, macvlan",
+		})
+		partialStatus := newPod("partial-status", map[string]string{
+			v1.NetworkAttachmentAnnot: "macvlan, macvlan",
+			v1.NetworkStatusAnnot:     `[{"name": "cbr0", "interface": "eth0"}, {"name": "testnamespace/macvlan", "interface": "net1"}]`,
+		})
+
+		attachments, err := ValidAttachmentsForNetwork(nad, []*corev1.Pod{repeated, partialStatus}, containerID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(attachments).To(Equal([]cnitypes.GCAttachment{
+			{ContainerID: "sandbox-repeated", IfName: "net1"},
+			{ContainerID: "sandbox-repeated", IfName: "net3"},
+			{ContainerID: "sandbox-partial-status", IfName: "net1"},
+			{ContainerID: "sandbox-partial-status", IfName: "net2"},
+		}))
+	})
+
+	It("skips pods without a sandbox on this node", func() {
+		pod := newPod("remote", map[string]string{v1.NetworkAttachmentAnnot: "macvlan"})
+		attachments, err := ValidAttachmentsForNetwork(nad, []*corev1.Pod{pod}, func(*corev1.Pod) (string, error) {
+			return "", nil
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(attachments).To(BeEmpty())
+	})
+
+	It("fails when a pod's attachments cannot be determined", func() {
+		pod := newPod("broken", map[string]string{v1.NetworkAttachmentAnnot: "[{invalid"})
+		_, err := ValidAttachmentsForNetwork(nad, []*corev1.Pod{pod}, containerID)
+		Expect(err).To(MatchError(ContainSubstring("testnamespace/broken")))
+	})
+})
@@ -0,0 +1,56 @@
+// Copyright (c) 2024 Kubernetes Network Plumbing Working Group
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/go-logr/logr"
+)
+
+// Verbosity levels of the package logs. Errors that are returned are not
+// logged.
+const (
+	// logLevelRetry logs retried API calls
+	logLevelRetry = 2
+	// logLevelDebug logs skipped config files and device info operations
+	logLevelDebug = 4
+)
+
+var packageLogger atomic.Pointer[logr.Logger]
+
+// SetLogger sets the logger of the package. Functions taking a context log
+// to the logger of the context, see logr.NewContext, and fall back to this
+// one. Nothing is logged until it is called.
+func SetLogger(logger logr.Logger) {
+	packageLogger.Store(&logger)
+}
+
+// logger returns the logger of the package, or one discarding the logs
+func logger() logr.Logger {
+	if l := packageLogger.Load(); l != nil {
+		return *l
+	}
+	return logr.Discard()
+}
+
+// loggerFrom returns the logger of ctx, or the logger of the package
+func loggerFrom(ctx context.Context) logr.Logger {
+	if l, err := logr.FromContext(ctx); err == nil {
+		return l
+	}
+	return logger()
+}
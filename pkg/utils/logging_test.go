@@ -0,0 +1,158 @@
+// Copyright (c) 2024 Kubernetes Network Plumbing Working Group
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/logr/funcr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	v1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Logging", func() {
+	var logs []string
+
+	newLogger := func(verbosity int) logr.Logger {
+		return funcr.New(func(prefix, args string) {
+			logs = append(logs, args)
+		}, funcr.Options{Verbosity: verbosity})
+	}
+
+	BeforeEach(func() {
+		logs = nil
+	})
+
+	AfterEach(func() {
+		SetLogger(logr.Discard())
+	})
+
+	It("logs retried network status updates", func() {
+		SetLogger(newLogger(logLevelRetry))
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod1", Namespace: "default"}}
+		client := fake.NewSimpleClientset(pod)
+		conflicts := 1
+		client.PrependReactor("update", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			if action.GetSubresource() != "status" || conflicts == 0 {
+				return false, nil, nil
+			}
+			conflicts--
+			return true, nil, apierrors.NewConflict(schema.GroupResource{Resource: "pods"}, "pod1", nil)
+		})
+
+		Expect(SetNetworkStatus(client, pod, []v1.NetworkStatus{{Name: "default/net1"}})).To(Succeed())
+		Expect(logs).To(HaveLen(1))
+		Expect(logs[0]).To(ContainSubstring(`"msg"="Retrying pod annotation update after a conflict"`))
+		Expect(logs[0]).To(ContainSubstring(`"attempt"=2`))
+	})
+
+	It("logs skipped config files only at debug verbosity", func() {
+		tmpDir, err := os.MkdirTemp("", "multus-tmp")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(tmpDir)
+		Expect(os.WriteFile(filepath.Join(tmpDir, "10-net1.conf"), []byte(`{"cniVersion": "1.0.0", "name": "net1", "type": "bridge"}`), 0644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(tmpDir, "20-net2.conf"), []byte(`{"cniVersion": "1.0.0", "name": "net2", "type": "bridge"}`), 0644)).To(Succeed())
+
+		SetLogger(newLogger(logLevelRetry))
+		_, err = GetCNIConfigFromFile("net2", tmpDir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(logs).To(BeEmpty())
+
+		SetLogger(newLogger(logLevelDebug))
+		_, err = GetCNIConfigFromFile("net2", tmpDir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(logs).To(HaveLen(1))
+		Expect(logs[0]).To(ContainSubstring(`"msg"="Skipping CNI config file of another network"`))
+		Expect(logs[0]).To(ContainSubstring(`"network"="net1"`))
+	})
+
+	It("logs device info operations", func() {
+		SetLogger(newLogger(logLevelDebug))
+		tmpDir, err := os.MkdirTemp("", "multus-tmp")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(tmpDir)
+		path := filepath.Join(tmpDir, "devinfo.json")
+
+		Expect(saveDeviceInfo(context.Background(), &v1.DeviceInfo{Type: v1.DeviceInfoTypePCI, Version: "1.1.0"}, path)).To(Succeed())
+		_, err = loadDeviceInfo(context.Background(), filepath.Join(tmpDir, "missing.json"))
+		Expect(err).To(HaveOccurred())
+		Expect(logs).To(HaveLen(2))
+		Expect(logs[0]).To(ContainSubstring(`"operation"="save"`))
+		Expect(logs[1]).To(ContainSubstring(`"msg"="Device info operation failed" "operation"="load"`))
+	})
+
+	It("prefers the logger of the context", func() {
+		SetLogger(newLogger(0))
+		var contextLogs []string
+		ctx := logr.NewContext(context.Background(), funcr.New(func(prefix, args string) {
+			contextLogs = append(contextLogs, args)
+		}, funcr.Options{}))
+
+		loggerFrom(ctx).Info("from context")
+		loggerFrom(context.Background()).Info("from package")
+		Expect(contextLogs).To(ConsistOf(ContainSubstring("from context")))
+		Expect(logs).To(ConsistOf(ContainSubstring("from package")))
+	})
+
+	It("logs the context variants to the logger of the context", func() {
+		SetLogger(newLogger(logLevelDebug))
+		var contextLogs []string
+		ctx := logr.NewContext(context.Background(), funcr.New(func(prefix, args string) {
+			contextLogs = append(contextLogs, args)
+		}, funcr.Options{Verbosity: logLevelDebug}))
+
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod1", Namespace: "default"}}
+		client := fake.NewSimpleClientset(pod)
+		conflicts := 1
+		client.PrependReactor("update", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			if action.GetSubresource() != "status" || conflicts == 0 {
+				return false, nil, nil
+			}
+			conflicts--
+			return true, nil, apierrors.NewConflict(schema.GroupResource{Resource: "pods"}, "pod1", nil)
+		})
+		Expect(SetNetworkStatusWithContext(ctx, client, pod, []v1.NetworkStatus{{Name: "default/net1"}})).To(Succeed())
+
+		tmpDir, err := os.MkdirTemp("", "multus-tmp")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(tmpDir)
+		Expect(os.WriteFile(filepath.Join(tmpDir, "10-net1.conf"), []byte(`{"cniVersion": "1.0.0", "name": "net1", "type": "bridge"}`), 0644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(tmpDir, "20-net2.conf"), []byte(`{"cniVersion": "1.0.0", "name": "net2", "type": "bridge"}`), 0644)).To(Succeed())
+		_, err = GetCNIConfigFromFileWithContext(ctx, "net2", tmpDir)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = LoadDeviceInfoFromCNIWithContext(ctx, filepath.Join(tmpDir, "missing.json"))
+		Expect(err).To(HaveOccurred())
+
+		Expect(logs).To(BeEmpty())
+		Expect(contextLogs).To(HaveLen(3))
+		Expect(contextLogs[0]).To(ContainSubstring(`"msg"="Retrying pod annotation update after a conflict"`))
+		Expect(contextLogs[1]).To(ContainSubstring(`"msg"="Skipping CNI config file of another network"`))
+		Expect(contextLogs[2]).To(ContainSubstring(`"msg"="Device info operation failed" "operation"="load"`))
+	})
+})
@@ -0,0 +1,103 @@
+// Copyright (c) 2024 Kubernetes Network Plumbing Working Group
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// Reasons passed to MetricsRecorder.IncAnnotationParseError
+const (
+	annotationErrorEmpty  = "empty"
+	annotationErrorJSON   = "json"
+	annotationErrorFormat = "format"
+	annotationErrorScope  = "scope"
+)
+
+// Operations passed to MetricsRecorder.IncDeviceInfoOperation
+const (
+	deviceInfoLoad  = "load"
+	deviceInfoSave  = "save"
+	deviceInfoClean = "clean"
+)
+
+// MetricsRecorder records the operations of the package. Implementations
+// must be safe for concurrent use; pkg/metrics provides one for Prometheus.
+type MetricsRecorder interface {
+	// ObserveNetworkStatusUpdate records a write of the network status of a
+	// pod and the number of conflicts it retried
+	ObserveNetworkStatusUpdate(duration time.Duration, retries int, err error)
+	// ObserveConfigFileLoad records a lookup of GetCNIConfigFromFile
+	ObserveConfigFileLoad(duration time.Duration, err error)
+	// IncAnnotationParseError counts a network selection annotation that
+	// failed to parse: reason is "empty", "json", "format" or "scope"
+	IncAnnotationParseError(reason string)
+	// IncDeviceInfoOperation counts a device info file operation: "load",
+	// "save" or "clean"
+	IncDeviceInfoOperation(operation string, err error)
+}
+
+type recorderHolder struct {
+	recorder MetricsRecorder
+}
+
+type recorderKey struct{}
+
+var metricsRecorder atomic.Pointer[recorderHolder]
+
+// SetMetricsRecorder sets the default recorder of the package operations.
+// It applies process-wide, to every caller of the package that does not
+// pass its own recorder with WithMetricsRecorder, so libraries should leave
+// it to the program. Nothing is recorded until it is called; nil disables
+// recording again.
+func SetMetricsRecorder(recorder MetricsRecorder) {
+	if recorder == nil {
+		metricsRecorder.Store(nil)
+		return
+	}
+	metricsRecorder.Store(&recorderHolder{recorder: recorder})
+}
+
+// WithMetricsRecorder returns a copy of ctx carrying recorder. Functions
+// taking a context record their operations to it instead of the default
+// recorder set with SetMetricsRecorder.
+func WithMetricsRecorder(ctx context.Context, recorder MetricsRecorder) context.Context {
+	return context.WithValue(ctx, recorderKey{}, recorder)
+}
+
+// recorder returns the default MetricsRecorder, or a no-op one
+func recorder() MetricsRecorder {
+	if holder := metricsRecorder.Load(); holder != nil {
+		return holder.recorder
+	}
+	return noopRecorder{}
+}
+
+// recorderFrom returns the MetricsRecorder of ctx, or the default one
+func recorderFrom(ctx context.Context) MetricsRecorder {
+	if r, ok := ctx.Value(recorderKey{}).(MetricsRecorder); ok && r != nil {
+		return r
+	}
+	return recorder()
+}
+
+type noopRecorder struct{}
+
+func (noopRecorder) ObserveNetworkStatusUpdate(time.Duration, int, error) {}
+func (noopRecorder) ObserveConfigFileLoad(time.Duration, error)           {}
+func (noopRecorder) IncAnnotationParseError(string)                       {}
+func (noopRecorder) IncDeviceInfoOperation(string, error)                 {}
@@ -17,10 +17,13 @@ package utils
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net"
 	"regexp"
 	"strings"
+	"time"
+	"unicode"
 
 	cnitypes "github.com/containernetworking/cni/pkg/types"
 	cni100 "github.com/containernetworking/cni/pkg/types/100"
@@ -31,6 +34,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
 )
 
 // convertDNS converts CNI's DNS type to client DNS
@@ -47,6 +51,13 @@ func convertDNS(dns cnitypes.DNS) *v1.DNS {
 
 // SetNetworkStatus updates the Pod status
 func SetNetworkStatus(client kubernetes.Interface, pod *corev1.Pod, statuses []v1.NetworkStatus) error {
+	return SetNetworkStatusWithContext(context.Background(), client, pod, statuses)
+}
+
+// SetNetworkStatusWithContext is SetNetworkStatus, updating the pod with ctx
+// and logging and recording the update to the logger and metrics recorder of
+// ctx
+func SetNetworkStatusWithContext(ctx context.Context, client kubernetes.Interface, pod *corev1.Pod, statuses []v1.NetworkStatus) error {
 	if client == nil {
 		return fmt.Errorf("no client set")
 	}
@@ -66,14 +77,23 @@ func SetNetworkStatus(client kubernetes.Interface, pod *corev1.Pod, statuses []v
 		}
 	}
 
-	err := setPodNetworkStatus(client, pod, fmt.Sprintf("[%s]", strings.Join(networkStatus, ",")))
+	err := setPodNetworkStatus(ctx, client, pod, fmt.Sprintf("[%s]", strings.Join(networkStatus, ",")))
 	if err != nil {
 		return fmt.Errorf("SetNetworkStatus: failed to update the pod %s in out of cluster comm: %v", pod.Name, err)
 	}
 	return nil
 }
 
-func setPodNetworkStatus(client kubernetes.Interface, pod *corev1.Pod, networkstatus string) error {
+func setPodNetworkStatus(ctx context.Context, client kubernetes.Interface, pod *corev1.Pod, networkstatus string) error {
+	start := time.Now()
+	retries, err := setPodAnnotation(ctx, client, pod, v1.NetworkStatusAnnot, networkstatus)
+	recorderFrom(ctx).ObserveNetworkStatusUpdate(time.Since(start), retries, err)
+	return err
+}
+
+// setPodAnnotation sets an annotation of the pod through the status
+// subresource, retrying on conflicts, and returns the number of retries
+func setPodAnnotation(ctx context.Context, client kubernetes.Interface, pod *corev1.Pod, key, value string) (int, error) {
 	if len(pod.Annotations) == 0 {
 		pod.Annotations = make(map[string]string)
 	}
@@ -83,8 +103,15 @@ func setPodNetworkStatus(client kubernetes.Interface, pod *corev1.Pod, networkst
 	name := pod.Name
 	namespace := pod.Namespace
 
+	log := loggerFrom(ctx)
+	attempts := 0
 	resultErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
-		pod, err = coreClient.Pods(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		attempts++
+		if attempts > 1 {
+			log.V(logLevelRetry).Info("Retrying pod annotation update after a conflict",
+				"pod", klog.KRef(namespace, name), "annotation", key, "attempt", attempts)
+		}
+		pod, err = coreClient.Pods(namespace).Get(ctx, name, metav1.GetOptions{})
 		if err != nil {
 			return err
 		}
@@ -92,14 +119,14 @@ func setPodNetworkStatus(client kubernetes.Interface, pod *corev1.Pod, networkst
 		if len(pod.Annotations) == 0 {
 			pod.Annotations = make(map[string]string)
 		}
-		pod.Annotations[v1.NetworkStatusAnnot] = networkstatus
-		_, err = coreClient.Pods(namespace).UpdateStatus(context.TODO(), pod, metav1.UpdateOptions{})
+		pod.Annotations[key] = value
+		_, err = coreClient.Pods(namespace).UpdateStatus(ctx, pod, metav1.UpdateOptions{})
 		return err
 	})
 	if resultErr != nil {
-		return fmt.Errorf("status update failed for pod %s/%s: %v", pod.Namespace, pod.Name, resultErr)
+		return attempts - 1, fmt.Errorf("status update failed for pod %s/%s: %v", pod.Namespace, pod.Name, resultErr)
 	}
-	return nil
+	return attempts - 1, nil
 }
 
 // GetNetworkStatus returns pod's network status
@@ -282,6 +309,12 @@ func isDefaultRoute(route *cnitypes.Route) bool {
 
 // ParsePodNetworkAnnotation parses Pod annotation for net-attach-def and get NetworkSelectionElement
 func ParsePodNetworkAnnotation(pod *corev1.Pod) ([]*v1.NetworkSelectionElement, error) {
+	return ParsePodNetworkAnnotationWithContext(context.Background(), pod)
+}
+
+// ParsePodNetworkAnnotationWithContext is ParsePodNetworkAnnotation,
+// recording parse errors to the metrics recorder of ctx
+func ParsePodNetworkAnnotationWithContext(ctx context.Context, pod *corev1.Pod) ([]*v1.NetworkSelectionElement, error) {
 	netAnnot := pod.Annotations[v1.NetworkAttachmentAnnot]
 	defaultNamespace := pod.Namespace
 
@@ -289,78 +322,157 @@ func ParsePodNetworkAnnotation(pod *corev1.Pod) ([]*v1.NetworkSelectionElement,
 		return nil, &v1.NoK8sNetworkError{Message: "no kubernetes network found"}
 	}
 
-	networks, err := ParseNetworkAnnotation(netAnnot, defaultNamespace)
+	networks, err := ParseNetworkAnnotationWithContext(ctx, netAnnot, defaultNamespace)
 	if err != nil {
 		return nil, err
 	}
 	return networks, nil
 }
 
-// ParseNetworkAnnotation parses actual annotation string and get NetworkSelectionElement
+// ParseNetworkAnnotation parses actual annotation string and get NetworkSelectionElement.
+// Errors are of type *InvalidAnnotationError.
 func ParseNetworkAnnotation(podNetworks, defaultNamespace string) ([]*v1.NetworkSelectionElement, error) {
+	return ParseNetworkAnnotationWithContext(context.Background(), podNetworks, defaultNamespace)
+}
+
+// ParseNetworkAnnotationWithContext is ParseNetworkAnnotation, recording
+// parse errors to the metrics recorder of ctx
+func ParseNetworkAnnotationWithContext(ctx context.Context, podNetworks, defaultNamespace string) ([]*v1.NetworkSelectionElement, error) {
 	var networks []*v1.NetworkSelectionElement
+	// positions holds the offset of every element of the comma-delimited format
+	var positions []int64
 
 	if podNetworks == "" {
-		return nil, fmt.Errorf("parsePodNetworkAnnotation: pod annotation not having \"network\" as key")
+		recorderFrom(ctx).IncAnnotationParseError(annotationErrorEmpty)
+		return nil, &InvalidAnnotationError{Index: -1, Position: -1,
+			Err: errors.New("pod annotation not having \"network\" as key")}
 	}
 
 	if strings.IndexAny(podNetworks, "[{\"") >= 0 {
 		if err := json.Unmarshal([]byte(podNetworks), &networks); err != nil {
-			return nil, fmt.Errorf("parsePodNetworkAnnotation: failed to parse pod Network Attachment Selection Annotation JSON format: %v", err)
+			recorderFrom(ctx).IncAnnotationParseError(annotationErrorJSON)
+			return nil, &InvalidAnnotationError{Annotation: podNetworks, Index: -1, Position: jsonErrorOffset(err),
+				Err: fmt.Errorf("failed to parse pod Network Attachment Selection Annotation JSON format: %w", err)}
 		}
 	} else {
 		// Comma-delimited list of network attachment object names
-		for _, item := range strings.Split(podNetworks, ",") {
+		position := 0
+		for i, item := range strings.Split(podNetworks, ",") {
+			itemPosition := position + leadingSpaces(item)
+			position += len(item) + 1
+
 			// Remove leading and trailing whitespace.
 			item = strings.TrimSpace(item)
 
+			// Cluster networks are referenced as cluster:<network name>@<ifname>
+			scope := ""
+			if strings.HasPrefix(item, v1.ClusterNetworkPrefix) {
+				scope = v1.NetworkScopeCluster
+				item = strings.TrimPrefix(item, v1.ClusterNetworkPrefix)
+				itemPosition += len(v1.ClusterNetworkPrefix)
+			}
+
 			// Parse network name (i.e. <namespace>/<network name>@<ifname>)
 			netNsName, networkName, netIfName, err := parsePodNetworkObjectText(item)
 			if err != nil {
-				return nil, fmt.Errorf("parsePodNetworkAnnotation: %v", err)
+				recorderFrom(ctx).IncAnnotationParseError(annotationErrorFormat)
+				err.Annotation = podNetworks
+				err.Index = i
+				err.Position += int64(itemPosition)
+				return nil, err
 			}
 
 			networks = append(networks, &v1.NetworkSelectionElement{
 				Name:             networkName,
 				Namespace:        netNsName,
 				InterfaceRequest: netIfName,
+				Scope:            scope,
 			})
+			positions = append(positions, int64(itemPosition))
 		}
 	}
 
-	for _, net := range networks {
-		if net.Namespace == "" {
-			net.Namespace = defaultNamespace
+	for i, net := range networks {
+		if net.Scope == v1.NetworkScopeCluster {
+			if net.Namespace != "" {
+				recorderFrom(ctx).IncAnnotationParseError(annotationErrorScope)
+				return nil, &InvalidAnnotationError{Annotation: podNetworks, Index: i, Position: elementPosition(positions, i),
+					Err: fmt.Errorf("cluster network %s cannot have a namespace", net.Name)}
+			}
+			continue
+		}
+		if net.Scope != "" {
+			recorderFrom(ctx).IncAnnotationParseError(annotationErrorScope)
+			return nil, &InvalidAnnotationError{Annotation: podNetworks, Index: i, Position: elementPosition(positions, i),
+				Err: fmt.Errorf("unknown scope %q for network %s", net.Scope, net.Name)}
 		}
+		v1.SetNetworkSelectionElementDefaults(net, defaultNamespace)
 	}
 
 	return networks, nil
 }
 
+// jsonErrorOffset returns the offset of a JSON decoding error, or -1
+func jsonErrorOffset(err error) int64 {
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return syntaxErr.Offset
+	}
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return typeErr.Offset
+	}
+	return -1
+}
+
+// elementPosition returns the offset of the i-th element, or -1 when unknown
+func elementPosition(positions []int64, i int) int64 {
+	if i < len(positions) {
+		return positions[i]
+	}
+	return -1
+}
+
+func leadingSpaces(s string) int {
+	return len(s) - len(strings.TrimLeftFunc(s, unicode.IsSpace))
+}
+
 // parsePodNetworkObjectText parses annotation text and returns
-// its triplet, (namespace, name, interface name).
-func parsePodNetworkObjectText(podnetwork string) (string, string, string, error) {
+// its triplet, (namespace, name, interface name). The Position of the
+// returned error is the offset of the invalid part in podnetwork.
+func parsePodNetworkObjectText(podnetwork string) (string, string, string, *InvalidAnnotationError) {
 	var netNsName string
 	var netIfName string
 	var networkName string
 
+	invalid := func(position int, err error) *InvalidAnnotationError {
+		return &InvalidAnnotationError{Annotation: podnetwork, Index: -1, Position: int64(position), Err: err}
+	}
+
+	// Offsets of the namespace, name and interface name in podnetwork
+	var nsPosition, namePosition, ifPosition int
+
 	slashItems := strings.Split(podnetwork, "/")
 	if len(slashItems) == 2 {
 		netNsName = strings.TrimSpace(slashItems[0])
+		nsPosition = leadingSpaces(slashItems[0])
 		networkName = slashItems[1]
+		namePosition = len(slashItems[0]) + 1
 	} else if len(slashItems) == 1 {
 		networkName = slashItems[0]
 	} else {
-		return "", "", "", fmt.Errorf("Invalid network object (failed at '/')")
+		return "", "", "", invalid(len(slashItems[0])+1+len(slashItems[1]), fmt.Errorf("Invalid network object (failed at '/')"))
 	}
 
 	atItems := strings.Split(networkName, "@")
 	networkName = strings.TrimSpace(atItems[0])
 	if len(atItems) == 2 {
 		netIfName = strings.TrimSpace(atItems[1])
+		ifPosition = namePosition + len(atItems[0]) + 1 + leadingSpaces(atItems[1])
 	} else if len(atItems) != 1 {
-		return "", "", "", fmt.Errorf("Invalid network object (failed at '@')")
+		return "", "", "", invalid(namePosition+len(atItems[0])+1+len(atItems[1]), fmt.Errorf("Invalid network object (failed at '@')"))
 	}
+	namePosition += leadingSpaces(atItems[0])
 
 	// Check and see if each item matches the specification for valid attachment name.
 	// "Valid attachment names must be comprised of units of the DNS-1123 label format"
@@ -368,10 +480,11 @@ func parsePodNetworkObjectText(podnetwork string) (string, string, string, error
 	// And we allow at (@), and forward slash (/) (units separated by commas)
 	// It must start and end alphanumerically.
 	allItems := []string{netNsName, networkName, netIfName}
+	allPositions := []int{nsPosition, namePosition, ifPosition}
 	for i := range allItems {
 		matched, _ := regexp.MatchString("^[a-z0-9]([-a-z0-9]*[a-z0-9])?$", allItems[i])
 		if !matched && len([]rune(allItems[i])) > 0 {
-			return "", "", "", fmt.Errorf(fmt.Sprintf("Failed to parse: one or more items did not match comma-delimited format (must consist of lower case alphanumeric characters). Must start and end with an alphanumeric character), mismatch @ '%v'", allItems[i]))
+			return "", "", "", invalid(allPositions[i], fmt.Errorf("Failed to parse: one or more items did not match comma-delimited format (must consist of lower case alphanumeric characters). Must start and end with an alphanumeric character), mismatch @ '%v'", allItems[i]))
 		}
 	}
 
@@ -0,0 +1,224 @@
+// Copyright (c) 2024 Kubernetes Network Plumbing Working Group
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/cache"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	v1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+	clientset "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/client/clientset/versioned"
+	listers "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/client/listers/k8s.cni.cncf.io/v1"
+)
+
+// networkCacheSize is the number of networks cached by the getter of
+// NewClientNetworkGetter
+const networkCacheSize = 1024
+
+// ResolvedNetwork is a network selection element of a pod along with the
+// network it references and its CNI configuration
+type ResolvedNetwork struct {
+	// Selection is the network selection element of the pod
+	Selection *v1.NetworkSelectionElement
+	// NAD is the network; cluster networks have no namespace, see
+	// ClusterNetworkToNetworkAttachmentDefinition
+	NAD *v1.NetworkAttachmentDefinition
+	// CNIConfig is the CNI configuration of the network, see GetCNIConfig
+	CNIConfig []byte
+}
+
+// NetworkGetter gets the networks referenced by network selection elements.
+// Both methods return a NotFound API error when the network does not exist.
+// NewListerNetworkGetter and NewClientNetworkGetter return implementations
+// backed by listers and by the typed client.
+type NetworkGetter interface {
+	GetNetworkAttachmentDefinition(ctx context.Context, namespace, name string) (*v1.NetworkAttachmentDefinition, error)
+	GetClusterNetworkAttachmentDefinition(ctx context.Context, name string) (*v1.ClusterNetworkAttachmentDefinition, error)
+}
+
+// ResolveNetworkSelections parses the network selection annotation of the
+// pod and gets the network of every element, like
+// ResolveNetworkAttachmentDefinition, along with its CNI configuration read
+// from the network or from confDir. Pods without networks resolve to none.
+// The annotation is parsed and the config files are looked up with the
+// logger and metrics recorder of ctx, see WithMetricsRecorder.
+//
+// The error aggregates the errors of every element that failed to resolve;
+// missing networks are reported as a *NetworkNotFoundError.
+func ResolveNetworkSelections(ctx context.Context, pod *corev1.Pod, getter NetworkGetter, confDir string) ([]ResolvedNetwork, error) {
+	if pod == nil {
+		return nil, fmt.Errorf("no pod set")
+	}
+	if getter == nil {
+		return nil, fmt.Errorf("no network getter set")
+	}
+
+	selections, err := ParsePodNetworkAnnotationWithContext(ctx, pod)
+	if err != nil {
+		var noNetworkErr *v1.NoK8sNetworkError
+		if errors.As(err, &noNetworkErr) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	resolved := make([]ResolvedNetwork, 0, len(selections))
+	var errs []error
+	for _, sel := range selections {
+		nad, err := getNetwork(ctx, sel, getter)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		config, err := GetCNIConfigWithContext(ctx, nad, confDir)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("network %s: %w", selectionRef(sel), err))
+			continue
+		}
+		resolved = append(resolved, ResolvedNetwork{Selection: sel, NAD: nad, CNIConfig: config})
+	}
+	if len(errs) > 0 {
+		return nil, utilerrors.NewAggregate(errs)
+	}
+	return resolved, nil
+}
+
+// getNetwork returns the network of the selection element, looking plain
+// references up in the element's namespace first and then in the cluster
+// networks
+func getNetwork(ctx context.Context, sel *v1.NetworkSelectionElement, getter NetworkGetter) (*v1.NetworkAttachmentDefinition, error) {
+	if sel.Scope != v1.NetworkScopeCluster {
+		nad, err := getter.GetNetworkAttachmentDefinition(ctx, sel.Namespace, sel.Name)
+		if err == nil {
+			return nad, nil
+		}
+		if !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to get network %s: %w", selectionRef(sel), err)
+		}
+	}
+
+	cnet, err := getter.GetClusterNetworkAttachmentDefinition(ctx, sel.Name)
+	if apierrors.IsNotFound(err) {
+		return nil, &NetworkNotFoundError{Name: sel.Name, Namespace: sel.Namespace, FromAPI: true}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cluster network %s: %w", sel.Name, err)
+	}
+	return ClusterNetworkToNetworkAttachmentDefinition(cnet), nil
+}
+
+func selectionRef(sel *v1.NetworkSelectionElement) string {
+	if sel.Scope == v1.NetworkScopeCluster {
+		return v1.ClusterNetworkPrefix + sel.Name
+	}
+	return sel.Namespace + "/" + sel.Name
+}
+
+type listerNetworkGetter struct {
+	nadLister     listers.NetworkAttachmentDefinitionLister
+	clusterLister listers.ClusterNetworkAttachmentDefinitionLister
+}
+
+// NewListerNetworkGetter returns a NetworkGetter reading from the listers of
+// informers. The cluster lister may be nil to disable cluster networks.
+func NewListerNetworkGetter(nadLister listers.NetworkAttachmentDefinitionLister, clusterLister listers.ClusterNetworkAttachmentDefinitionLister) NetworkGetter {
+	return &listerNetworkGetter{nadLister: nadLister, clusterLister: clusterLister}
+}
+
+func (g *listerNetworkGetter) GetNetworkAttachmentDefinition(_ context.Context, namespace, name string) (*v1.NetworkAttachmentDefinition, error) {
+	nad, err := g.nadLister.NetworkAttachmentDefinitions(namespace).Get(name)
+	if err != nil {
+		return nil, err
+	}
+	return nad.DeepCopy(), nil
+}
+
+func (g *listerNetworkGetter) GetClusterNetworkAttachmentDefinition(_ context.Context, name string) (*v1.ClusterNetworkAttachmentDefinition, error) {
+	if g.clusterLister == nil {
+		return nil, apierrors.NewNotFound(v1.Resource("cluster-network-attachment-definitions"), name)
+	}
+	cnet, err := g.clusterLister.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	return cnet.DeepCopy(), nil
+}
+
+type clientNetworkGetter struct {
+	client clientset.Interface
+	ttl    time.Duration
+	cache  *cache.LRUExpireCache
+}
+
+// NewClientNetworkGetter returns a NetworkGetter reading from the API server
+// with the typed client, for callers without informers. Networks are cached
+// for ttl, so that resolving the networks of consecutive pods does not get
+// the same network every time; a ttl of 0 disables the cache. Missing
+// networks are not cached, so networks created right before their pods are
+// found.
+func NewClientNetworkGetter(client clientset.Interface, ttl time.Duration) NetworkGetter {
+	return &clientNetworkGetter{
+		client: client,
+		ttl:    ttl,
+		cache:  cache.NewLRUExpireCache(networkCacheSize),
+	}
+}
+
+func (g *clientNetworkGetter) GetNetworkAttachmentDefinition(ctx context.Context, namespace, name string) (*v1.NetworkAttachmentDefinition, error) {
+	obj, err := g.get(v1.Resource("network-attachment-definitions"), namespace, name, func() (interface{}, error) {
+		return g.client.K8sCniCncfIoV1().NetworkAttachmentDefinitions(namespace).Get(ctx, name, metav1.GetOptions{})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return obj.(*v1.NetworkAttachmentDefinition).DeepCopy(), nil
+}
+
+func (g *clientNetworkGetter) GetClusterNetworkAttachmentDefinition(ctx context.Context, name string) (*v1.ClusterNetworkAttachmentDefinition, error) {
+	obj, err := g.get(v1.Resource("cluster-network-attachment-definitions"), "", name, func() (interface{}, error) {
+		return g.client.K8sCniCncfIoV1().ClusterNetworkAttachmentDefinitions().Get(ctx, name, metav1.GetOptions{})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return obj.(*v1.ClusterNetworkAttachmentDefinition).DeepCopy(), nil
+}
+
+// get returns the cached object, or gets and caches it
+func (g *clientNetworkGetter) get(resource schema.GroupResource, namespace, name string, getFn func() (interface{}, error)) (interface{}, error) {
+	key := resource.String() + "/" + namespace + "/" + name
+	if g.ttl > 0 {
+		if obj, ok := g.cache.Get(key); ok {
+			return obj, nil
+		}
+	}
+	obj, err := getFn()
+	if err != nil {
+		return nil, err
+	}
+	if g.ttl > 0 {
+		g.cache.Add(key, obj, g.ttl)
+	}
+	return obj, nil
+}
@@ -0,0 +1,170 @@
+// Copyright (c) 2024 Kubernetes Network Plumbing Working Group
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/tools/cache"
+
+	v1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+	"github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/client/clientset/versioned/fake"
+	listers "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/client/listers/k8s.cni.cncf.io/v1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Network selection resolution", func() {
+	var nad *v1.NetworkAttachmentDefinition
+	var cnet *v1.ClusterNetworkAttachmentDefinition
+
+	podWithNetworks := func(networks string) *corev1.Pod {
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod1", Namespace: "tenant"}}
+		if networks != "" {
+			pod.Annotations = map[string]string{v1.NetworkAttachmentAnnot: networks}
+		}
+		return pod
+	}
+
+	BeforeEach(func() {
+		nad = &v1.NetworkAttachmentDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "net1", Namespace: "tenant"},
+			Spec:       v1.NetworkAttachmentDefinitionSpec{Config: `{"cniVersion": "1.0.0", "type": "bridge"}`},
+		}
+		cnet = &v1.ClusterNetworkAttachmentDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "platform"},
+			Spec:       v1.NetworkAttachmentDefinitionSpec{Config: `{"cniVersion": "1.0.0", "type": "macvlan"}`},
+		}
+	})
+
+	Context("with listers", func() {
+		var getter NetworkGetter
+
+		BeforeEach(func() {
+			nadIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			Expect(nadIndexer.Add(nad)).To(Succeed())
+			clusterIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			Expect(clusterIndexer.Add(cnet)).To(Succeed())
+			getter = NewListerNetworkGetter(listers.NewNetworkAttachmentDefinitionLister(nadIndexer),
+				listers.NewClusterNetworkAttachmentDefinitionLister(clusterIndexer))
+		})
+
+		It("resolves namespaced and cluster networks with their config", func() {
+			resolved, err := ResolveNetworkSelections(context.Background(), podWithNetworks("net1@eth1, cluster:platform, platform"), getter, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resolved).To(HaveLen(3))
+
+			Expect(resolved[0].Selection.InterfaceRequest).To(Equal("eth1"))
+			Expect(resolved[0].NAD.Namespace).To(Equal("tenant"))
+			Expect(resolved[0].CNIConfig).To(MatchJSON(`{"cniVersion": "1.0.0", "name": "net1", "type": "bridge"}`))
+
+			for _, network := range resolved[1:] {
+				Expect(network.NAD.Name).To(Equal("platform"))
+				Expect(network.NAD.Namespace).To(BeEmpty())
+				Expect(network.CNIConfig).To(MatchJSON(`{"cniVersion": "1.0.0", "name": "platform", "type": "macvlan"}`))
+			}
+		})
+
+		It("resolves pods without networks to none", func() {
+			resolved, err := ResolveNetworkSelections(context.Background(), podWithNetworks(""), getter, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resolved).To(BeEmpty())
+		})
+
+		It("reports every missing network", func() {
+			_, err := ResolveNetworkSelections(context.Background(), podWithNetworks("net1, missing, other/net1, cluster:net1"), getter, "")
+			var agg utilerrors.Aggregate
+			Expect(errors.As(err, &agg)).To(BeTrue())
+			Expect(agg.Errors()).To(HaveLen(3))
+			Expect(err).To(MatchError("[network tenant/missing not found, network other/net1 not found, cluster network net1 not found]"))
+
+			var notFoundErr *NetworkNotFoundError
+			Expect(errors.As(agg.Errors()[1], &notFoundErr)).To(BeTrue())
+			Expect(notFoundErr.Namespace).To(Equal("other"))
+			Expect(notFoundErr.Name).To(Equal("net1"))
+		})
+
+		It("fails on invalid annotations", func() {
+			_, err := ResolveNetworkSelections(context.Background(), podWithNetworks("a/b/c"), getter, "")
+			var annotErr *InvalidAnnotationError
+			Expect(errors.As(err, &annotErr)).To(BeTrue())
+		})
+	})
+
+	Context("with the typed client", func() {
+		var client *fake.Clientset
+
+		BeforeEach(func() {
+			// Objects are created through the client, as the tracker of the
+			// fake clientset guesses other resource names than the client's
+			client = fake.NewSimpleClientset()
+		})
+
+		create := func(nad *v1.NetworkAttachmentDefinition, cnet *v1.ClusterNetworkAttachmentDefinition) {
+			if nad != nil {
+				_, err := client.K8sCniCncfIoV1().NetworkAttachmentDefinitions(nad.Namespace).Create(context.Background(), nad, metav1.CreateOptions{})
+				Expect(err).NotTo(HaveOccurred())
+			}
+			if cnet != nil {
+				_, err := client.K8sCniCncfIoV1().ClusterNetworkAttachmentDefinitions().Create(context.Background(), cnet, metav1.CreateOptions{})
+				Expect(err).NotTo(HaveOccurred())
+			}
+			client.ClearActions()
+		}
+
+		It("caches networks for the ttl", func() {
+			create(nad, cnet)
+			getter := NewClientNetworkGetter(client, time.Minute)
+			pod := podWithNetworks("net1, cluster:platform")
+
+			for i := 0; i < 2; i++ {
+				resolved, err := ResolveNetworkSelections(context.Background(), pod, getter, "")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resolved).To(HaveLen(2))
+				resolved[0].NAD.Spec.Config = "modified"
+			}
+			Expect(client.Actions()).To(HaveLen(2))
+		})
+
+		It("does not cache missing networks", func() {
+			getter := NewClientNetworkGetter(client, time.Minute)
+			pod := podWithNetworks("net1")
+
+			_, err := ResolveNetworkSelections(context.Background(), pod, getter, "")
+			Expect(err).To(MatchError("network tenant/net1 not found"))
+
+			create(nad, nil)
+			resolved, err := ResolveNetworkSelections(context.Background(), pod, getter, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resolved).To(HaveLen(1))
+		})
+
+		It("gets networks every time without a ttl", func() {
+			create(nad, nil)
+			getter := NewClientNetworkGetter(client, 0)
+			for i := 0; i < 2; i++ {
+				_, err := getter.GetNetworkAttachmentDefinition(context.Background(), "tenant", "net1")
+				Expect(err).NotTo(HaveOccurred())
+			}
+			Expect(client.Actions()).To(HaveLen(2))
+		})
+	})
+})
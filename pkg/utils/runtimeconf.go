@@ -0,0 +1,159 @@
+// Copyright (c) 2024 Kubernetes Network Plumbing Working Group
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/containernetworking/cni/libcni"
+
+	v1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+)
+
+// PodInfo identifies the pod sandbox a network is attached to
+type PodInfo struct {
+	Namespace   string
+	Name        string
+	UID         string
+	ContainerID string
+	NetNS       string
+}
+
+// BuildRuntimeConf creates the libcni RuntimeConf used to invoke the network
+// selected by sel for the given pod, in the same way Multus does: pod
+// identity is passed as CNI_ARGS and the selection requests as capability
+// args. GatewayRequest is applied by the runtime to the ADD result and is
+// not part of the runtime config; CNIArgs are injected into the CNI config
+// with InjectCNIArgs.
+func BuildRuntimeConf(sel *v1.NetworkSelectionElement, podInfo *PodInfo, ifname string) (*libcni.RuntimeConf, error) {
+	if podInfo == nil {
+		return nil, fmt.Errorf("no pod info set")
+	}
+
+	rt := &libcni.RuntimeConf{
+		ContainerID: podInfo.ContainerID,
+		NetNS:       podInfo.NetNS,
+		IfName:      ifname,
+		Args: [][2]string{
+			{"IgnoreUnknown", "true"},
+			{"K8S_POD_NAMESPACE", podInfo.Namespace},
+			{"K8S_POD_NAME", podInfo.Name},
+			{"K8S_POD_INFRA_CONTAINER_ID", podInfo.ContainerID},
+			{"K8S_POD_UID", podInfo.UID},
+		},
+	}
+	if sel == nil {
+		return rt, nil
+	}
+
+	capabilityArgs := make(map[string]interface{})
+	if sel.MacRequest != "" {
+		if _, err := net.ParseMAC(sel.MacRequest); err != nil {
+			return nil, fmt.Errorf("failed to parse mac address %q: %v", sel.MacRequest, err)
+		}
+		rt.Args = append(rt.Args, [2]string{"MAC", sel.MacRequest})
+		capabilityArgs[CapabilityMAC] = sel.MacRequest
+	}
+	if sel.InfinibandGUIDRequest != "" {
+		capabilityArgs[CapabilityInfinibandGUID] = sel.InfinibandGUIDRequest
+	}
+	if len(sel.IPRequest) > 0 {
+		for _, ip := range sel.IPRequest {
+			if _, err := parseRequestedIP(ip); err != nil {
+				return nil, fmt.Errorf("failed to parse IP address %q: %v", ip, err)
+			}
+		}
+		rt.Args = append(rt.Args, [2]string{"IP", strings.Join(sel.IPRequest, ",")})
+		capabilityArgs[CapabilityIPs] = sel.IPRequest
+	}
+	if sel.BandwidthRequest != nil {
+		capabilityArgs[CapabilityBandwidth] = sel.BandwidthRequest
+	}
+	if len(sel.PortMappingsRequest) > 0 {
+		capabilityArgs[CapabilityPortMappings] = sel.PortMappingsRequest
+	}
+	if len(capabilityArgs) > 0 {
+		rt.CapabilityArgs = capabilityArgs
+	}
+
+	return rt, nil
+}
+
+// InjectCNIArgs adds the selection's CNIArgs to the "args.cni" section of a
+// CNI config, or of every plugin of a config list. Keys already present in
+// "args.cni" are overridden by the selection.
+func InjectCNIArgs(config []byte, sel *v1.NetworkSelectionElement) ([]byte, error) {
+	if sel == nil || sel.CNIArgs == nil || len(*sel.CNIArgs) == 0 {
+		return config, nil
+	}
+
+	// the config is decoded without converting numbers to floating point,
+	// which would corrupt large integers
+	rawConfig, err := decodeConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal CNI config: %v", err)
+	}
+
+	if plugins, ok := rawConfig["plugins"]; ok {
+		pluginList, ok := plugins.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid \"plugins\" in CNI config list")
+		}
+		for i, plugin := range pluginList {
+			pluginConfig, ok := plugin.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("invalid plugin %d in CNI config list", i)
+			}
+			if err := injectCNIArgs(pluginConfig, *sel.CNIArgs); err != nil {
+				return nil, fmt.Errorf("plugin %d: %v", i, err)
+			}
+		}
+	} else if err := injectCNIArgs(rawConfig, *sel.CNIArgs); err != nil {
+		return nil, err
+	}
+
+	configBytes, err := marshalCanonical(rawConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to re-marshal CNI config: %v", err)
+	}
+	return configBytes, nil
+}
+
+func injectCNIArgs(pluginConfig map[string]interface{}, cniArgs map[string]interface{}) error {
+	args, ok := pluginConfig["args"].(map[string]interface{})
+	if !ok {
+		if _, exists := pluginConfig["args"]; exists {
+			return fmt.Errorf("invalid \"args\" in CNI config")
+		}
+		args = make(map[string]interface{})
+		pluginConfig["args"] = args
+	}
+
+	cni, ok := args["cni"].(map[string]interface{})
+	if !ok {
+		if _, exists := args["cni"]; exists {
+			return fmt.Errorf("invalid \"args.cni\" in CNI config")
+		}
+		cni = make(map[string]interface{})
+		args["cni"] = cni
+	}
+
+	for k, v := range cniArgs {
+		cni[k] = v
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2024 Kubernetes Network Plumbing Working Group
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	v1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CNI runtime config", func() {
+	podInfo := &PodInfo{
+		Namespace:   "testnamespace",
+		Name:        "testpod",
+		UID:         "c9b2f6a2-7d1b-4a2f-9e8f-2b9ad2d3a0a1",
+		ContainerID: "123456789",
+		NetNS:       "/var/run/netns/test",
+	}
+
+	It("passes pod identity as CNI_ARGS", func() {
+		rt, err := BuildRuntimeConf(&v1.NetworkSelectionElement{Name: "net1"}, podInfo, "net1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rt.ContainerID).To(Equal("123456789"))
+		Expect(rt.NetNS).To(Equal("/var/run/netns/test"))
+		Expect(rt.IfName).To(Equal("net1"))
+		Expect(rt.Args).To(Equal([][2]string{
+			{"IgnoreUnknown", "true"},
+			{"K8S_POD_NAMESPACE", "testnamespace"},
+			{"K8S_POD_NAME", "testpod"},
+			{"K8S_POD_INFRA_CONTAINER_ID", "123456789"},
+			{"K8S_POD_UID", "c9b2f6a2-7d1b-4a2f-9e8f-2b9ad2d3a0a1"},
+		}))
+		Expect(rt.CapabilityArgs).To(BeNil())
+	})
+
+	It("passes selection requests as capability args", func() {
+		sel := &v1.NetworkSelectionElement{
+			Name:                  "net1",
+			IPRequest:             []string{"10.1.1.5/24", "2001::5/64"},
+			MacRequest:            "c2:b0:57:49:47:f1",
+			InfinibandGUIDRequest: "c2:b0:57:49:47:f1:00:01",
+			BandwidthRequest:      &v1.BandwidthEntry{IngressRate: 1000},
+			PortMappingsRequest:   []*v1.PortMapEntry{{HostPort: 8080, ContainerPort: 80}},
+		}
+		rt, err := BuildRuntimeConf(sel, podInfo, "net1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rt.Args).To(ContainElement([2]string{"MAC", "c2:b0:57:49:47:f1"}))
+		Expect(rt.Args).To(ContainElement([2]string{"IP", "10.1.1.5/24,2001::5/64"}))
+		Expect(rt.CapabilityArgs).To(HaveKeyWithValue("ips", sel.IPRequest))
+		Expect(rt.CapabilityArgs).To(HaveKeyWithValue("mac", sel.MacRequest))
+		Expect(rt.CapabilityArgs).To(HaveKeyWithValue("infinibandGUID", sel.InfinibandGUIDRequest))
+		Expect(rt.CapabilityArgs).To(HaveKeyWithValue("bandwidth", sel.BandwidthRequest))
+		Expect(rt.CapabilityArgs).To(HaveKeyWithValue("portMappings", sel.PortMappingsRequest))
+	})
+
+	It("rejects an invalid mac request", func() {
+		_, err := BuildRuntimeConf(&v1.NetworkSelectionElement{Name: "net1", MacRequest: "invalid"}, podInfo, "net1")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("injects cni-args into every plugin of a config list", func() {
+		cniArgs := map[string]interface{}{"foo": "bar"}
+		sel := &v1.NetworkSelectionElement{Name: "net1", CNIArgs: &cniArgs}
+		config, err := InjectCNIArgs([]byte(`{
+			"cniVersion": "0.4.0",
+			"name": "net1",
+			"plugins": [
+				{"type": "macvlan", "args": {"cni": {"foo": "old", "keep": "me"}}},
+				{"type": "tuning"}
+			]
+		}`), sel)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config).To(MatchJSON(`{
+			"cniVersion": "0.4.0",
+			"name": "net1",
+			"plugins": [
+				{"type": "macvlan", "args": {"cni": {"foo": "bar", "keep": "me"}}},
+				{"type": "tuning", "args": {"cni": {"foo": "bar"}}}
+			]
+		}`))
+	})
+
+	It("keeps large integers when injecting cni-args", func() {
+		cniArgs := map[string]interface{}{"foo": "bar"}
+		sel := &v1.NetworkSelectionElement{Name: "net1", CNIArgs: &cniArgs}
+		config, err := InjectCNIArgs([]byte(`{"type": "vlan", "name": "net1", "vlanId": 9007199254740993}`), sel)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(config)).To(ContainSubstring(`"vlanId":9007199254740993`))
+	})
+
+	It("leaves the config untouched without cni-args", func() {
+		config := []byte(`{"type": "macvlan", "name": "net1"}`)
+		injected, err := InjectCNIArgs(config, &v1.NetworkSelectionElement{Name: "net1"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(injected).To(Equal(config))
+	})
+})
@@ -0,0 +1,175 @@
+// Copyright (c) 2024 Kubernetes Network Plumbing Working Group
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/netip"
+	"strings"
+
+	v1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+	"github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/ipam"
+)
+
+// CNI capabilities used to pass NetworkSelectionElement requests to plugins
+const (
+	CapabilityIPs            = "ips"
+	CapabilityMAC            = "mac"
+	CapabilityPortMappings   = "portMappings"
+	CapabilityBandwidth      = "bandwidth"
+	CapabilityInfinibandGUID = "infinibandGUID"
+)
+
+// GetCNICapabilities returns the union of the capabilities enabled by the
+// plugins of a CNI config or config list
+func GetCNICapabilities(config []byte) (map[string]bool, error) {
+	type plugin struct {
+		Capabilities map[string]bool `json:"capabilities,omitempty"`
+	}
+	var conf struct {
+		plugin
+		Plugins []plugin `json:"plugins,omitempty"`
+	}
+
+	if err := json.Unmarshal(config, &conf); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal CNI config: %v", err)
+	}
+
+	plugins := conf.Plugins
+	if len(plugins) == 0 {
+		plugins = []plugin{conf.plugin}
+	}
+
+	capabilities := make(map[string]bool)
+	for _, p := range plugins {
+		for name, enabled := range p.Capabilities {
+			if enabled {
+				capabilities[name] = true
+			}
+		}
+	}
+	return capabilities, nil
+}
+
+// CheckSelectionAgainstNAD checks that the requests in the network selection
+// element can be honoured by the NetworkAttachmentDefinition's CNI config.
+// It returns an error describing every requested field that would be silently
+// ignored, or nil when all of them are supported.
+func CheckSelectionAgainstNAD(sel *v1.NetworkSelectionElement, nad *v1.NetworkAttachmentDefinition) error {
+	if sel == nil {
+		return fmt.Errorf("no network selection element set")
+	}
+	if nad == nil {
+		return fmt.Errorf("no network attachment definition set")
+	}
+	if nad.Spec.Config == "" {
+		return fmt.Errorf("network %s/%s has no Spec.Config to check against", nad.Namespace, nad.Name)
+	}
+
+	config := []byte(nad.Spec.Config)
+	capabilities, err := GetCNICapabilities(config)
+	if err != nil {
+		return fmt.Errorf("network %s/%s: %v", nad.Namespace, nad.Name, err)
+	}
+	ipamConfigs, err := ipam.GetIPAMConfigs(config)
+	if err != nil {
+		return fmt.Errorf("network %s/%s: %v", nad.Namespace, nad.Name, err)
+	}
+
+	var ignored []string
+	missingCapability := func(field, capability string) {
+		ignored = append(ignored, fmt.Sprintf("%s (no plugin supports the %q capability)", field, capability))
+	}
+
+	if len(sel.IPRequest) > 0 {
+		if problems := checkIPRequest(sel.IPRequest, capabilities, ipamConfigs); len(problems) > 0 {
+			ignored = append(ignored, problems...)
+		}
+	}
+	if sel.MacRequest != "" && !capabilities[CapabilityMAC] {
+		missingCapability("mac", CapabilityMAC)
+	}
+	if len(sel.PortMappingsRequest) > 0 && !capabilities[CapabilityPortMappings] {
+		missingCapability("portMappings", CapabilityPortMappings)
+	}
+	if sel.BandwidthRequest != nil && !capabilities[CapabilityBandwidth] {
+		missingCapability("bandwidth", CapabilityBandwidth)
+	}
+	if sel.InfinibandGUIDRequest != "" && !capabilities[CapabilityInfinibandGUID] {
+		missingCapability("infiniband-guid", CapabilityInfinibandGUID)
+	}
+
+	if len(ignored) > 0 {
+		return fmt.Errorf("network %s/%s will ignore requested %s", nad.Namespace, nad.Name, strings.Join(ignored, ", "))
+	}
+	return nil
+}
+
+// checkIPRequest verifies that requested IPs can be passed to the IPAM plugin
+// and, for range based IPAM, that they fall in one of the configured ranges,
+// between its rangeStart and rangeEnd when set
+func checkIPRequest(ips []string, capabilities map[string]bool, ipamConfigs []*ipam.Config) []string {
+	isStatic := false
+	var ranges []ipam.Range
+	for _, conf := range ipamConfigs {
+		if conf.Type == ipam.TypeStatic {
+			isStatic = true
+		}
+		if conf.Type == ipam.TypeHostLocal || conf.Type == ipam.TypeWhereabouts {
+			ranges = append(ranges, conf.Ranges...)
+		}
+	}
+
+	if !capabilities[CapabilityIPs] && !isStatic {
+		return []string{fmt.Sprintf("ips (IPAM is not static and no plugin supports the %q capability)", CapabilityIPs)}
+	}
+
+	var problems []string
+	for _, ip := range ips {
+		addr, err := parseRequestedIP(ip)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("ips (invalid address %q: %v)", ip, err))
+			continue
+		}
+		if len(ranges) == 0 {
+			continue
+		}
+		inRange := false
+		for _, rng := range ranges {
+			if rng.Subnet.Contains(addr) && addr.Compare(rng.First()) >= 0 && addr.Compare(rng.Last()) <= 0 {
+				inRange = true
+				break
+			}
+		}
+		if !inRange {
+			problems = append(problems, fmt.Sprintf("ips (%s is not in any configured IPAM range)", ip))
+		}
+	}
+	return problems
+}
+
+// parseRequestedIP accepts both plain addresses and the CIDR notation used
+// by static IPAM
+func parseRequestedIP(ip string) (netip.Addr, error) {
+	if strings.Contains(ip, "/") {
+		prefix, err := netip.ParsePrefix(ip)
+		if err != nil {
+			return netip.Addr{}, err
+		}
+		return prefix.Addr(), nil
+	}
+	return netip.ParseAddr(ip)
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2024 Kubernetes Network Plumbing Working Group
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	v1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Network selection checks", func() {
+	newNAD := func(config string) *v1.NetworkAttachmentDefinition {
+		return &v1.NetworkAttachmentDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-net",
+				Namespace: "testnamespace",
+			},
+			Spec: v1.NetworkAttachmentDefinitionSpec{
+				Config: config,
+			},
+		}
+	}
+
+	It("accepts requests supported by the plugin capabilities", func() {
+		nad := newNAD(`{
+			"cniVersion": "0.4.0",
+			"name": "test-net",
+			"plugins": [
+				{"type": "macvlan", "capabilities": {"ips": true}, "ipam": {"type": "static"}},
+				{"type": "tuning", "capabilities": {"mac": true}},
+				{"type": "portmap", "capabilities": {"portMappings": true}}
+			]
+		}`)
+		sel := &v1.NetworkSelectionElement{
+			Name:                "test-net",
+			IPRequest:           []string{"10.1.1.5/24"},
+			MacRequest:          "c2:b0:57:49:47:f1",
+			PortMappingsRequest: []*v1.PortMapEntry{{HostPort: 8080, ContainerPort: 80}},
+		}
+		Expect(CheckSelectionAgainstNAD(sel, nad)).To(Succeed())
+	})
+
+	It("reports every request that would be ignored", func() {
+		nad := newNAD(`{"cniVersion": "0.4.0", "type": "macvlan", "ipam": {"type": "dhcp"}}`)
+		sel := &v1.NetworkSelectionElement{
+			Name:                  "test-net",
+			IPRequest:             []string{"10.1.1.5"},
+			MacRequest:            "c2:b0:57:49:47:f1",
+			BandwidthRequest:      &v1.BandwidthEntry{IngressRate: 1000},
+			InfinibandGUIDRequest: "c2:b0:57:49:47:f1:00:01",
+		}
+		err := CheckSelectionAgainstNAD(sel, nad)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("ips"))
+		Expect(err.Error()).To(ContainSubstring(`"mac" capability`))
+		Expect(err.Error()).To(ContainSubstring(`"bandwidth" capability`))
+		Expect(err.Error()).To(ContainSubstring(`"infinibandGUID" capability`))
+	})
+
+	It("reports requested IPs outside of the IPAM ranges", func() {
+		nad := newNAD(`{
+			"cniVersion": "0.4.0",
+			"type": "macvlan",
+			"capabilities": {"ips": true},
+			"ipam": {"type": "whereabouts", "range": "10.1.1.0/24"}
+		}`)
+		sel := &v1.NetworkSelectionElement{
+			Name:      "test-net",
+			IPRequest: []string{"10.1.1.5/24", "10.2.0.1/24"},
+		}
+		err := CheckSelectionAgainstNAD(sel, nad)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("10.2.0.1/24 is not in any configured IPAM range"))
+		Expect(err.Error()).NotTo(ContainSubstring("10.1.1.5/24"))
+	})
+
+	It("reports requested IPs outside of the allocatable part of the IPAM ranges", func() {
+		nad := newNAD(`{
+			"cniVersion": "0.4.0",
+			"type": "macvlan",
+			"capabilities": {"ips": true},
+			"ipam": {"type": "host-local", "ranges": [[{"subnet": "10.1.1.0/24", "rangeStart": "10.1.1.100", "rangeEnd": "10.1.1.200"}]]}
+		}`)
+		sel := &v1.NetworkSelectionElement{
+			Name:      "test-net",
+			IPRequest: []string{"10.1.1.150/24", "10.1.1.5/24", "10.1.1.201"},
+		}
+		err := CheckSelectionAgainstNAD(sel, nad)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("10.1.1.5/24 is not in any configured IPAM range"))
+		Expect(err.Error()).To(ContainSubstring("10.1.1.201 is not in any configured IPAM range"))
+		Expect(err.Error()).NotTo(ContainSubstring("10.1.1.150"))
+	})
+})
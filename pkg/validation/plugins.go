@@ -0,0 +1,385 @@
+// Copyright (c) 2024 Kubernetes Network Plumbing Working Group
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validation
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	v1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+	"github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/ipam"
+)
+
+const (
+	// maxIfNameLen is the longest Linux interface name (IFNAMSIZ - 1)
+	maxIfNameLen = 15
+	// maxVlanID is the highest usable 802.1Q VLAN ID
+	maxVlanID = 4094
+)
+
+var builtinPluginValidators = map[string]PluginValidator{
+	"bridge":      PluginValidatorFunc(validateBridge),
+	"macvlan":     PluginValidatorFunc(validateMacvlan),
+	"ipvlan":      PluginValidatorFunc(validateIpvlan),
+	"vlan":        PluginValidatorFunc(validateVlan),
+	"host-device": PluginValidatorFunc(validateHostDevice),
+	"sriov":       PluginValidatorFunc(validateSriov),
+	"tuning":      PluginValidatorFunc(validateTuning),
+	"portmap":     PluginValidatorFunc(validatePortmap),
+	"bandwidth":   PluginValidatorFunc(validateBandwidth),
+}
+
+var builtinIPAMValidators = map[string]PluginValidator{
+	ipam.TypeHostLocal: PluginValidatorFunc(validateHostLocal),
+	ipam.TypeStatic:    PluginValidatorFunc(validateStatic),
+	ipam.TypeDHCP:      PluginValidatorFunc(validateDHCP),
+}
+
+func decode(plugin *PluginConfig, conf interface{}) error {
+	if err := json.Unmarshal(plugin.Raw, conf); err != nil {
+		return fmt.Errorf("failed to unmarshal config: %v", err)
+	}
+	return nil
+}
+
+func checkIfName(field, name string) error {
+	if name == "" {
+		return nil
+	}
+	if len(name) > maxIfNameLen {
+		return fmt.Errorf("%s %q is longer than %d characters", field, name, maxIfNameLen)
+	}
+	if strings.ContainsAny(name, "/: \t\n") {
+		return fmt.Errorf("%s %q is not a valid interface name", field, name)
+	}
+	return nil
+}
+
+func checkVlan(field string, vlan int) error {
+	if vlan < 0 || vlan > maxVlanID {
+		return fmt.Errorf("%s %d is out of range 0-%d", field, vlan, maxVlanID)
+	}
+	return nil
+}
+
+func checkMTU(mtu int) error {
+	if mtu < 0 {
+		return fmt.Errorf("mtu %d is negative", mtu)
+	}
+	return nil
+}
+
+func checkOneOf(field, value string, allowed ...string) error {
+	for _, a := range allowed {
+		if value == a {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s %q is not one of %s", field, value, strings.Join(allowed, ", "))
+}
+
+func validateBridge(plugin *PluginConfig) error {
+	conf := struct {
+		Bridge string `json:"bridge"`
+		Vlan   int    `json:"vlan"`
+		MTU    int    `json:"mtu"`
+	}{}
+	if err := decode(plugin, &conf); err != nil {
+		return err
+	}
+	return utilerrors.NewAggregate([]error{
+		checkIfName("bridge", conf.Bridge),
+		checkVlan("vlan", conf.Vlan),
+		checkMTU(conf.MTU),
+	})
+}
+
+func validateMacvlan(plugin *PluginConfig) error {
+	conf := struct {
+		Master string `json:"master"`
+		Mode   string `json:"mode"`
+		MTU    int    `json:"mtu"`
+	}{}
+	if err := decode(plugin, &conf); err != nil {
+		return err
+	}
+	return utilerrors.NewAggregate([]error{
+		checkIfName("master", conf.Master),
+		checkOneOf("mode", conf.Mode, "", "bridge", "private", "vepa", "passthru"),
+		checkMTU(conf.MTU),
+	})
+}
+
+func validateIpvlan(plugin *PluginConfig) error {
+	conf := struct {
+		Master string `json:"master"`
+		Mode   string `json:"mode"`
+		MTU    int    `json:"mtu"`
+	}{}
+	if err := decode(plugin, &conf); err != nil {
+		return err
+	}
+	return utilerrors.NewAggregate([]error{
+		checkIfName("master", conf.Master),
+		checkOneOf("mode", conf.Mode, "", "l2", "l3", "l3s"),
+		checkMTU(conf.MTU),
+	})
+}
+
+func validateVlan(plugin *PluginConfig) error {
+	conf := struct {
+		Master string `json:"master"`
+		VlanID int    `json:"vlanId"`
+		MTU    int    `json:"mtu"`
+	}{}
+	if err := decode(plugin, &conf); err != nil {
+		return err
+	}
+	return utilerrors.NewAggregate([]error{
+		checkIfName("master", conf.Master),
+		checkVlan("vlanId", conf.VlanID),
+		checkMTU(conf.MTU),
+	})
+}
+
+func validateHostDevice(plugin *PluginConfig) error {
+	conf := struct {
+		Device     string `json:"device"`
+		HWAddr     string `json:"hwaddr"`
+		KernelPath string `json:"kernelpath"`
+		PCIBusID   string `json:"pciBusID"`
+	}{}
+	if err := decode(plugin, &conf); err != nil {
+		return err
+	}
+
+	set := 0
+	for _, v := range []string{conf.Device, conf.HWAddr, conf.KernelPath, conf.PCIBusID} {
+		if v != "" {
+			set++
+		}
+	}
+	if set > 1 {
+		return fmt.Errorf("only one of device, hwaddr, kernelpath and pciBusID may be set")
+	}
+	if conf.HWAddr != "" {
+		if _, err := net.ParseMAC(conf.HWAddr); err != nil {
+			return fmt.Errorf("invalid hwaddr %q: %v", conf.HWAddr, err)
+		}
+	}
+	return checkIfName("device", conf.Device)
+}
+
+func validateSriov(plugin *PluginConfig) error {
+	conf := struct {
+		Vlan      int    `json:"vlan"`
+		VlanQoS   int    `json:"vlanQoS"`
+		SpoofChk  string `json:"spoofchk"`
+		Trust     string `json:"trust"`
+		LinkState string `json:"link_state"`
+		MinTxRate *int   `json:"min_tx_rate"`
+		MaxTxRate *int   `json:"max_tx_rate"`
+	}{}
+	if err := decode(plugin, &conf); err != nil {
+		return err
+	}
+
+	var errs []error
+	if plugin.Network != nil && plugin.Network.Annotations[v1.ResourceNameAnnot] == "" {
+		errs = append(errs, fmt.Errorf("network has no %s annotation", v1.ResourceNameAnnot))
+	}
+	errs = append(errs, checkVlan("vlan", conf.Vlan))
+	if conf.VlanQoS < 0 || conf.VlanQoS > 7 {
+		errs = append(errs, fmt.Errorf("vlanQoS %d is out of range 0-7", conf.VlanQoS))
+	}
+	errs = append(errs,
+		checkOneOf("spoofchk", conf.SpoofChk, "", "on", "off"),
+		checkOneOf("trust", conf.Trust, "", "on", "off"),
+		checkOneOf("link_state", conf.LinkState, "", "auto", "enable", "disable"),
+	)
+	if conf.MinTxRate != nil && conf.MaxTxRate != nil && *conf.MaxTxRate != 0 && *conf.MinTxRate > *conf.MaxTxRate {
+		errs = append(errs, fmt.Errorf("min_tx_rate %d is above max_tx_rate %d", *conf.MinTxRate, *conf.MaxTxRate))
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+func validateTuning(plugin *PluginConfig) error {
+	conf := struct {
+		MAC    string            `json:"mac"`
+		MTU    int               `json:"mtu"`
+		SysCtl map[string]string `json:"sysctl"`
+	}{}
+	if err := decode(plugin, &conf); err != nil {
+		return err
+	}
+
+	var errs []error
+	if conf.MAC != "" {
+		if _, err := net.ParseMAC(conf.MAC); err != nil {
+			errs = append(errs, fmt.Errorf("invalid mac %q: %v", conf.MAC, err))
+		}
+	}
+	errs = append(errs, checkMTU(conf.MTU))
+	for key := range conf.SysCtl {
+		if !strings.HasPrefix(key, "net.") {
+			errs = append(errs, fmt.Errorf("sysctl %q is not a network sysctl", key))
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+func validatePortmap(plugin *PluginConfig) error {
+	conf := struct {
+		RuntimeConfig struct {
+			PortMappings []struct {
+				HostPort      int    `json:"hostPort"`
+				ContainerPort int    `json:"containerPort"`
+				Protocol      string `json:"protocol"`
+			} `json:"portMappings"`
+		} `json:"runtimeConfig"`
+	}{}
+	if err := decode(plugin, &conf); err != nil {
+		return err
+	}
+
+	var errs []error
+	for i, pm := range conf.RuntimeConfig.PortMappings {
+		if pm.HostPort < 1 || pm.HostPort > 65535 {
+			errs = append(errs, fmt.Errorf("portMappings[%d]: hostPort %d is out of range 1-65535", i, pm.HostPort))
+		}
+		if pm.ContainerPort < 1 || pm.ContainerPort > 65535 {
+			errs = append(errs, fmt.Errorf("portMappings[%d]: containerPort %d is out of range 1-65535", i, pm.ContainerPort))
+		}
+		if err := checkOneOf("protocol", strings.ToLower(pm.Protocol), "", "tcp", "udp", "sctp"); err != nil {
+			errs = append(errs, fmt.Errorf("portMappings[%d]: %v", i, err))
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+func validateBandwidth(plugin *PluginConfig) error {
+	conf := struct {
+		IngressRate  uint64 `json:"ingressRate"`
+		IngressBurst uint64 `json:"ingressBurst"`
+		EgressRate   uint64 `json:"egressRate"`
+		EgressBurst  uint64 `json:"egressBurst"`
+	}{}
+	if err := decode(plugin, &conf); err != nil {
+		return err
+	}
+
+	var errs []error
+	if (conf.IngressRate == 0) != (conf.IngressBurst == 0) {
+		errs = append(errs, fmt.Errorf("ingressRate and ingressBurst must be set together"))
+	}
+	if (conf.EgressRate == 0) != (conf.EgressBurst == 0) {
+		errs = append(errs, fmt.Errorf("egressRate and egressBurst must be set together"))
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+func validateHostLocal(plugin *PluginConfig) error {
+	type rangeConf struct {
+		Subnet     string `json:"subnet"`
+		RangeStart string `json:"rangeStart"`
+		RangeEnd   string `json:"rangeEnd"`
+		Gateway    string `json:"gateway"`
+	}
+	conf := struct {
+		rangeConf
+		Ranges [][]rangeConf `json:"ranges"`
+	}{}
+	if err := decode(plugin, &conf); err != nil {
+		return err
+	}
+
+	ranges := conf.Ranges
+	if conf.Subnet != "" {
+		ranges = append(ranges, []rangeConf{conf.rangeConf})
+	}
+	if len(ranges) == 0 {
+		return fmt.Errorf("no subnet or ranges set")
+	}
+
+	var errs []error
+	for i, set := range ranges {
+		for j, r := range set {
+			_, subnet, err := net.ParseCIDR(r.Subnet)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("ranges[%d][%d]: invalid subnet %q", i, j, r.Subnet))
+				continue
+			}
+			for _, field := range []struct{ name, addr string }{
+				{"rangeStart", r.RangeStart},
+				{"rangeEnd", r.RangeEnd},
+				{"gateway", r.Gateway},
+			} {
+				if field.addr == "" {
+					continue
+				}
+				if ip := net.ParseIP(field.addr); ip == nil || !subnet.Contains(ip) {
+					errs = append(errs, fmt.Errorf("ranges[%d][%d]: %s %q is not an address in %s", i, j, field.name, field.addr, subnet))
+				}
+			}
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+func validateStatic(plugin *PluginConfig) error {
+	conf := struct {
+		Addresses []struct {
+			Address string `json:"address"`
+			Gateway string `json:"gateway"`
+		} `json:"addresses"`
+		Routes []struct {
+			Dst string `json:"dst"`
+			GW  string `json:"gw"`
+		} `json:"routes"`
+	}{}
+	if err := decode(plugin, &conf); err != nil {
+		return err
+	}
+
+	var errs []error
+	for i, a := range conf.Addresses {
+		if _, _, err := net.ParseCIDR(a.Address); err != nil {
+			errs = append(errs, fmt.Errorf("addresses[%d]: invalid address %q", i, a.Address))
+		}
+		if a.Gateway != "" && net.ParseIP(a.Gateway) == nil {
+			errs = append(errs, fmt.Errorf("addresses[%d]: invalid gateway %q", i, a.Gateway))
+		}
+	}
+	for i, r := range conf.Routes {
+		if _, _, err := net.ParseCIDR(r.Dst); err != nil {
+			errs = append(errs, fmt.Errorf("routes[%d]: invalid dst %q", i, r.Dst))
+		}
+		if r.GW != "" && net.ParseIP(r.GW) == nil {
+			errs = append(errs, fmt.Errorf("routes[%d]: invalid gw %q", i, r.GW))
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+func validateDHCP(plugin *PluginConfig) error {
+	conf := struct {
+		DaemonSocketPath string `json:"daemonSocketPath"`
+	}{}
+	return decode(plugin, &conf)
+}
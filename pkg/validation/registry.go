@@ -0,0 +1,216 @@
+// Copyright (c) 2024 Kubernetes Network Plumbing Working Group
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package validation validates the plugin specific parts of CNI
+// configurations, such as a macvlan mode or a vlan ID, using validators
+// registered by plugin type.
+package validation
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/kube-openapi/pkg/validation/strfmt"
+	"k8s.io/kube-openapi/pkg/validation/validate"
+
+	v1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+)
+
+// PluginConfig is the configuration of one plugin, or of its IPAM section,
+// handed to a PluginValidator
+type PluginConfig struct {
+	// Type is the plugin type, or the IPAM type for IPAM validators
+	Type string
+	// Raw is the JSON configuration of the plugin or IPAM section
+	Raw []byte
+	// Network is the network the configuration belongs to. It is nil when
+	// a configuration is validated on its own.
+	Network *v1.NetworkAttachmentDefinition
+}
+
+// PluginValidator validates the configuration of one plugin type
+type PluginValidator interface {
+	ValidatePlugin(plugin *PluginConfig) error
+}
+
+// PluginValidatorFunc adapts a function to a PluginValidator
+type PluginValidatorFunc func(plugin *PluginConfig) error
+
+// ValidatePlugin calls f(plugin)
+func (f PluginValidatorFunc) ValidatePlugin(plugin *PluginConfig) error {
+	return f(plugin)
+}
+
+// Registry holds the validators of plugin and IPAM types. Plugins of types
+// without a validator are not checked. It is safe for concurrent use.
+type Registry struct {
+	mu      sync.RWMutex
+	plugins map[string]PluginValidator
+	ipam    map[string]PluginValidator
+}
+
+// NewRegistry creates a Registry without any validator
+func NewRegistry() *Registry {
+	return &Registry{
+		plugins: map[string]PluginValidator{},
+		ipam:    map[string]PluginValidator{},
+	}
+}
+
+// NewDefaultRegistry creates a Registry with the validators of the reference
+// plugins and IPAM plugins
+func NewDefaultRegistry() *Registry {
+	r := NewRegistry()
+	for pluginType, validator := range builtinPluginValidators {
+		r.Register(pluginType, validator)
+	}
+	for ipamType, validator := range builtinIPAMValidators {
+		r.RegisterIPAM(ipamType, validator)
+	}
+	return r
+}
+
+// Register sets the validator of a plugin type, replacing any previous one
+func (r *Registry) Register(pluginType string, validator PluginValidator) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.plugins[pluginType] = validator
+}
+
+// RegisterIPAM sets the validator of an IPAM type, replacing any previous one
+func (r *Registry) RegisterIPAM(ipamType string, validator PluginValidator) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ipam[ipamType] = validator
+}
+
+// RegisterSchema sets a JSON schema (OpenAPI v3 flavour) as the validator of
+// a plugin type
+func (r *Registry) RegisterSchema(pluginType string, schema []byte) error {
+	validator, err := NewSchemaValidator(schema)
+	if err != nil {
+		return fmt.Errorf("invalid schema for plugin type %s: %v", pluginType, err)
+	}
+	r.Register(pluginType, validator)
+	return nil
+}
+
+func (r *Registry) pluginValidator(pluginType string) PluginValidator {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.plugins[pluginType]
+}
+
+func (r *Registry) ipamValidator(ipamType string) PluginValidator {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.ipam[ipamType]
+}
+
+// Validate validates every plugin of the network's spec.config. Networks
+// without a config, whose config lives in the CNI config directory, are not
+// checked.
+func (r *Registry) Validate(net *v1.NetworkAttachmentDefinition) error {
+	if net.Spec.Config == "" {
+		return nil
+	}
+	return r.ValidateConfig([]byte(net.Spec.Config), net)
+}
+
+// ValidateConfig validates every plugin of a CNI config or config list, and
+// their IPAM sections. The network may be nil. All failures are returned as
+// an aggregate error.
+func (r *Registry) ValidateConfig(config []byte, net *v1.NetworkAttachmentDefinition) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(config, &fields); err != nil {
+		return fmt.Errorf("failed to unmarshal config: %v", err)
+	}
+
+	plugins := []json.RawMessage{config}
+	prefix := func(int) string { return "" }
+	if raw, ok := fields["plugins"]; ok {
+		if err := json.Unmarshal(raw, &plugins); err != nil {
+			return fmt.Errorf("failed to unmarshal plugins: %v", err)
+		}
+		prefix = func(i int) string { return fmt.Sprintf("plugins[%d]: ", i) }
+	}
+
+	var errs []error
+	for i, raw := range plugins {
+		if err := r.validatePlugin(raw, net); err != nil {
+			errs = append(errs, fmt.Errorf("%s%v", prefix(i), err))
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+func (r *Registry) validatePlugin(raw []byte, net *v1.NetworkAttachmentDefinition) error {
+	var plugin struct {
+		Type string          `json:"type"`
+		IPAM json.RawMessage `json:"ipam"`
+	}
+	if err := json.Unmarshal(raw, &plugin); err != nil {
+		return fmt.Errorf("failed to unmarshal plugin: %v", err)
+	}
+	if plugin.Type == "" {
+		return fmt.Errorf("missing plugin type")
+	}
+
+	var errs []error
+	if validator := r.pluginValidator(plugin.Type); validator != nil {
+		if err := validator.ValidatePlugin(&PluginConfig{Type: plugin.Type, Raw: raw, Network: net}); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %v", plugin.Type, err))
+		}
+	}
+
+	if len(plugin.IPAM) > 0 && string(plugin.IPAM) != "null" {
+		var ipam struct {
+			Type string `json:"type"`
+		}
+		if err := json.Unmarshal(plugin.IPAM, &ipam); err != nil {
+			errs = append(errs, fmt.Errorf("failed to unmarshal ipam: %v", err))
+		} else if validator := r.ipamValidator(ipam.Type); validator != nil {
+			if err := validator.ValidatePlugin(&PluginConfig{Type: ipam.Type, Raw: plugin.IPAM, Network: net}); err != nil {
+				errs = append(errs, fmt.Errorf("ipam %s: %v", ipam.Type, err))
+			}
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// schemaValidator validates configurations against a JSON schema
+type schemaValidator struct {
+	validator *validate.SchemaValidator
+}
+
+// NewSchemaValidator creates a PluginValidator checking configurations
+// against the JSON schema
+func NewSchemaValidator(schema []byte) (PluginValidator, error) {
+	s := &spec.Schema{}
+	if err := json.Unmarshal(schema, s); err != nil {
+		return nil, err
+	}
+	return &schemaValidator{validator: validate.NewSchemaValidator(s, nil, "", strfmt.Default)}, nil
+}
+
+func (v *schemaValidator) ValidatePlugin(plugin *PluginConfig) error {
+	var data interface{}
+	if err := json.Unmarshal(plugin.Raw, &data); err != nil {
+		return err
+	}
+	return utilerrors.NewAggregate(v.validator.Validate(data).Errors)
+}
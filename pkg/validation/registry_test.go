@@ -0,0 +1,120 @@
+// Copyright (c) 2024 Kubernetes Network Plumbing Working Group
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validation
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Plugin validation registry", func() {
+	var registry *Registry
+
+	BeforeEach(func() {
+		registry = NewDefaultRegistry()
+	})
+
+	DescribeTable("validates the reference plugins",
+		func(config, expectedError string) {
+			err := registry.ValidateConfig([]byte(config), nil)
+			if expectedError == "" {
+				Expect(err).NotTo(HaveOccurred())
+			} else {
+				Expect(err).To(MatchError(ContainSubstring(expectedError)))
+			}
+		},
+		Entry("valid bridge", `{"type": "bridge", "bridge": "br0", "vlan": 100}`, ""),
+		Entry("bridge name too long", `{"type": "bridge", "bridge": "a-very-long-bridge-name"}`, "longer than 15"),
+		Entry("macvlan mode typo", `{"type": "macvlan", "master": "eth0", "mode": "bridg"}`, `mode "bridg"`),
+		Entry("ipvlan mode", `{"type": "ipvlan", "master": "eth0", "mode": "l4"}`, `mode "l4"`),
+		Entry("vlan ID over 4094", `{"type": "vlan", "master": "eth0", "vlanId": 4095}`, "vlanId 4095"),
+		Entry("vlan without master", `{"type": "vlan", "vlanId": 10}`, ""),
+		Entry("vlan ID 0", `{"type": "vlan", "master": "eth0", "vlanId": 0}`, ""),
+		Entry("negative vlan ID", `{"type": "vlan", "master": "eth0", "vlanId": -1}`, "vlanId -1"),
+		Entry("host-device with two selectors", `{"type": "host-device", "device": "eth1", "pciBusID": "0000:00:01.0"}`, "only one of"),
+		Entry("tuning with non network sysctl", `{"type": "tuning", "sysctl": {"kernel.panic": "1"}}`, `sysctl "kernel.panic"`),
+		Entry("portmap with bad port", `{"type": "portmap", "runtimeConfig": {"portMappings": [{"hostPort": 70000, "containerPort": 80}]}}`, "hostPort 70000"),
+		Entry("bandwidth rate without burst", `{"type": "bandwidth", "ingressRate": 1000}`, "ingressRate and ingressBurst"),
+		Entry("wrong field type", `{"type": "vlan", "master": "eth0", "vlanId": "10"}`, "failed to unmarshal"),
+		Entry("unknown plugin types are accepted", `{"type": "my-plugin", "anything": true}`, ""),
+		Entry("missing type", `{"master": "eth0"}`, "missing plugin type"),
+		Entry("host-local gateway outside subnet", `{"type": "bridge", "ipam": {"type": "host-local", "subnet": "10.0.0.0/24", "gateway": "10.0.1.1"}}`, `gateway "10.0.1.1"`),
+		Entry("host-local without ranges", `{"type": "bridge", "ipam": {"type": "host-local"}}`, "no subnet or ranges"),
+		Entry("valid host-local ranges", `{"type": "bridge", "ipam": {"type": "host-local", "ranges": [[{"subnet": "10.0.0.0/24", "rangeStart": "10.0.0.10"}]]}}`, ""),
+		Entry("static invalid address", `{"type": "macvlan", "ipam": {"type": "static", "addresses": [{"address": "10.0.0.1"}]}}`, `invalid address "10.0.0.1"`),
+		Entry("valid dhcp", `{"type": "macvlan", "master": "eth0", "ipam": {"type": "dhcp"}}`, ""),
+	)
+
+	It("validates every plugin of a config list", func() {
+		err := registry.ValidateConfig([]byte(`{
+			"cniVersion": "1.0.0",
+			"name": "net1",
+			"plugins": [
+				{"type": "macvlan", "mode": "bridg"},
+				{"type": "tuning", "mtu": 1400},
+				{"type": "bandwidth", "egressRate": 10}
+			]
+		}`), nil)
+		Expect(err).To(MatchError(ContainSubstring("plugins[0]: macvlan")))
+		Expect(err).To(MatchError(ContainSubstring("plugins[2]: bandwidth")))
+		Expect(err).NotTo(MatchError(ContainSubstring("plugins[1]")))
+	})
+
+	It("requires the resource name annotation for sriov networks", func() {
+		nad := &v1.NetworkAttachmentDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "sriov-net", Namespace: "default"},
+			Spec:       v1.NetworkAttachmentDefinitionSpec{Config: `{"cniVersion": "1.0.0", "type": "sriov", "vlan": 10}`},
+		}
+		Expect(registry.Validate(nad)).To(MatchError(ContainSubstring(v1.ResourceNameAnnot)))
+
+		nad.Annotations = map[string]string{v1.ResourceNameAnnot: "intel.com/sriov"}
+		Expect(registry.Validate(nad)).To(Succeed())
+
+		nad.Spec.Config = ""
+		nad.Annotations = nil
+		Expect(registry.Validate(nad)).To(Succeed())
+	})
+
+	It("uses registered Go and JSON schema validators", func() {
+		registry.Register("my-plugin", PluginValidatorFunc(func(plugin *PluginConfig) error {
+			return fmt.Errorf("rejected %s", plugin.Type)
+		}))
+		Expect(registry.ValidateConfig([]byte(`{"type": "my-plugin"}`), nil)).To(MatchError(ContainSubstring("rejected my-plugin")))
+
+		Expect(registry.RegisterSchema("schema-plugin", []byte(`{
+			"type": "object",
+			"required": ["device"],
+			"properties": {
+				"device": {"type": "string"},
+				"queues": {"type": "integer", "minimum": 1}
+			}
+		}`))).To(Succeed())
+		Expect(registry.ValidateConfig([]byte(`{"type": "schema-plugin", "device": "eth0", "queues": 2}`), nil)).To(Succeed())
+		Expect(registry.ValidateConfig([]byte(`{"type": "schema-plugin", "queues": 0}`), nil)).To(HaveOccurred())
+
+		Expect(registry.RegisterSchema("broken", []byte(`{"type": `))).NotTo(Succeed())
+	})
+
+	It("starts empty without the default validators", func() {
+		Expect(NewRegistry().ValidateConfig([]byte(`{"type": "vlan", "vlanId": 5000}`), nil)).To(Succeed())
+	})
+})